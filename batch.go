@@ -0,0 +1,328 @@
+package runware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBatchConcurrency = 4
+	maxBatchLineSize        = 1024 * 1024
+)
+
+// BatchTask a NewImage task of a batch input line. ID identifies the task across runs,
+// it defaults to the TaskUUID or the line number
+type BatchTask struct {
+	ID string `json:"id,omitempty"`
+	NewTaskReq
+}
+
+// BatchResult outcome of a batch task, written as one output line
+type BatchResult struct {
+	ID         string    `json:"id"`
+	Line       int       `json:"line"`
+	TaskUUID   string    `json:"taskUUID,omitempty"`
+	Images     []Image   `json:"images,omitempty"`
+	Manifest   *Manifest `json:"manifest,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// BatchOptions options of RunBatch
+type BatchOptions struct {
+	// Concurrency max tasks running at once, defaults to 4
+	Concurrency int
+	// Completed IDs of tasks to skip, e.g. from ReadBatchResults
+	Completed map[string]bool
+	// OnResult is called after each result is written
+	OnResult func(res BatchResult)
+}
+
+// BatchSummary counts of a batch run
+type BatchSummary struct {
+	Total     int `json:"total"`
+	Skipped   int `json:"skipped"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// RunBatch runs the tasks read from the JSONL input and writes a result line per task to out as
+// soon as it finishes. Tasks interrupted by the context cancellation are not written, so the
+// batch can be resumed by skipping the completed ones
+func (sdk *SDK) RunBatch(ctx context.Context, in io.Reader, out io.Writer, opts BatchOptions) (BatchSummary, error) {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+	
+	var (
+		summary  BatchSummary
+		mu       sync.Mutex
+		enc      = json.NewEncoder(out)
+		writeErr error
+	)
+	write := func(res BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		
+		if res.Error != "" {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+		if err := enc.Encode(res); err != nil && writeErr == nil {
+			writeErr = err
+		}
+		if opts.OnResult != nil {
+			opts.OnResult(res)
+		}
+	}
+	
+	type lineTask struct {
+		line int
+		task BatchTask
+	}
+	tasks := make(chan lineTask)
+	
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lt := range tasks {
+				res, ok := sdk.runBatchTask(ctx, lt.line, lt.task)
+				if ok {
+					write(res)
+				}
+			}
+		}()
+	}
+	
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+	
+	line := 0
+	for scanner.Scan() && ctx.Err() == nil {
+		line++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+		
+		summary.Total++
+		var task BatchTask
+		if err := json.Unmarshal(raw, &task); err != nil {
+			write(BatchResult{
+				ID:         batchTaskID(task, line),
+				Line:       line,
+				Error:      fmt.Errorf("%w:[line %d][%s]", ErrDecodeMessage, line, err).Error(),
+				FinishedAt: time.Now(),
+			})
+			continue
+		}
+		task.ID = batchTaskID(task, line)
+		
+		if opts.Completed[task.ID] {
+			mu.Lock()
+			summary.Skipped++
+			mu.Unlock()
+			continue
+		}
+		
+		select {
+		case tasks <- lineTask{line: line, task: task}:
+		case <-ctx.Done():
+		}
+	}
+	close(tasks)
+	wg.Wait()
+	
+	if err := scanner.Err(); err != nil {
+		return summary, err
+	}
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+	return summary, writeErr
+}
+
+// runBatchTask runs a task, false is returned when it was interrupted by the context
+func (sdk *SDK) runBatchTask(ctx context.Context, line int, task BatchTask) (BatchResult, bool) {
+	res := BatchResult{
+		ID:       task.ID,
+		Line:     line,
+		TaskUUID: task.TaskUUID,
+	}
+	
+	resp, err := sdk.NewImage(ctx, task.NewTaskReq)
+	// A timed out task is recorded as failed, its remaining images are not awaited
+	if resp != nil && resp.Pending != nil {
+		resp.Pending.Cancel()
+	}
+	if ctx.Err() != nil {
+		return res, false
+	}
+	
+	if resp != nil {
+		res.Images = resp.Images
+		res.Manifest = resp.Manifest
+		if resp.Manifest != nil {
+			res.TaskUUID = resp.Manifest.Request.TaskUUID
+		}
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	res.FinishedAt = time.Now()
+	return res, true
+}
+
+func batchTaskID(task BatchTask, line int) string {
+	switch {
+	case task.ID != "":
+		return task.ID
+	case task.TaskUUID != "":
+		return task.TaskUUID
+	default:
+		return "line-" + strconv.Itoa(line)
+	}
+}
+
+// ReadBatchResults reads the results written by RunBatch. A truncated last line, left by a crash, is ignored
+func ReadBatchResults(r io.Reader) ([]BatchResult, error) {
+	var (
+		results []BatchResult
+		pending error
+	)
+	
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		// Only the last line may be truncated
+		if pending != nil {
+			return nil, pending
+		}
+		
+		var res BatchResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			pending = fmt.Errorf("%w:[%s]", ErrDecodeMessage, err)
+			continue
+		}
+		results = append(results, res)
+	}
+	
+	return results, scanner.Err()
+}
+
+// BatchFileOptions options of RunBatchFile
+type BatchFileOptions struct {
+	BatchOptions
+	// RetryFailed runs again the tasks whose previous result is an error
+	RetryFailed bool
+}
+
+// RunBatchFile runs the tasks of the input JSONL file and appends the results to the output file.
+// When the output file exists, the tasks it already holds results for are skipped
+func (sdk *SDK) RunBatchFile(ctx context.Context, inputPath, outputPath string, opts BatchFileOptions) (BatchSummary, error) {
+	in, err := os.Open(inputPath)
+	if err != nil {
+		return BatchSummary{}, err
+	}
+	defer in.Close()
+	
+	completed, err := completedBatchTasks(outputPath, opts.RetryFailed)
+	if err != nil {
+		return BatchSummary{}, err
+	}
+	for id := range opts.Completed {
+		completed[id] = true
+	}
+	opts.Completed = completed
+	
+	out, err := os.OpenFile(outputPath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return BatchSummary{}, err
+	}
+	defer out.Close()
+	
+	if err = trimPartialLine(out); err != nil {
+		return BatchSummary{}, err
+	}
+	
+	// Results are synced to disk as checkpoints
+	onResult := opts.OnResult
+	opts.OnResult = func(res BatchResult) {
+		_ = out.Sync()
+		if onResult != nil {
+			onResult(res)
+		}
+	}
+	
+	return sdk.RunBatch(ctx, in, out, opts.BatchOptions)
+}
+
+func completedBatchTasks(outputPath string, retryFailed bool) (map[string]bool, error) {
+	completed := make(map[string]bool)
+	
+	f, err := os.Open(outputPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	
+	results, err := ReadBatchResults(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		if res.Error == "" || !retryFailed {
+			completed[res.ID] = true
+		}
+	}
+	return completed, nil
+}
+
+// trimPartialLine truncates a partial last line left by a crash, so new results start on their own line
+func trimPartialLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	
+	var (
+		size = info.Size()
+		buf  = make([]byte, 4096)
+	)
+	for end := size; end > 0; {
+		start := max(end-int64(len(buf)), 0)
+		chunk := buf[:end-start]
+		if _, err = f.ReadAt(chunk, start); err != nil {
+			return err
+		}
+		
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			if keep := start + int64(i) + 1; keep < size {
+				return f.Truncate(keep)
+			}
+			return nil
+		}
+		end = start
+	}
+	
+	return f.Truncate(0)
+}
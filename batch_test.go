@@ -0,0 +1,105 @@
+package runware
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+const batchInput = `{"id":"cat","promptText":"A cat","numberResults":2}
+{"promptText":"A dog","numberResults":1,"taskUUID":"dog-task"}
+
+{"promptText":""}
+not json
+{"promptText":"A bird","numberResults":1}
+`
+
+func TestRunBatch(t *testing.T) {
+	server := newFakeServer().answerImages(2)
+	sdk := server.sdk()
+	
+	out := &bytes.Buffer{}
+	summary, err := sdk.RunBatch(context.Background(), strings.NewReader(batchInput), out, BatchOptions{
+		Concurrency: 2,
+		Completed:   map[string]bool{"line-6": true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BatchSummary{Total: 5, Skipped: 1, Succeeded: 2, Failed: 2}, summary)
+	assert.Len(t, receivedFake[NewTaskReq](server, NewTask), 2)
+	
+	results, err := ReadBatchResults(out)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	
+	byID := make(map[string]BatchResult)
+	for _, res := range results {
+		byID[res.ID] = res
+	}
+	assert.Len(t, byID["cat"].Images, 2)
+	assert.Equal(t, "dog-task", byID["dog-task"].TaskUUID)
+	assert.Contains(t, byID["line-4"].Error, ErrFieldRequired.Error())
+	assert.Contains(t, byID["line-5"].Error, ErrDecodeMessage.Error())
+}
+
+func TestRunBatchFileResume(t *testing.T) {
+	server := newFakeServer().answerImages(2)
+	sdk := server.sdk()
+	
+	dir := t.TempDir()
+	input := filepath.Join(dir, "tasks.jsonl")
+	output := filepath.Join(dir, "results.jsonl")
+	assert.NoError(t, os.WriteFile(input, []byte(batchInput), 0o644))
+	
+	// A previous run crashed while writing the dog result
+	assert.NoError(t, os.WriteFile(output, []byte(`{"id":"cat","line":1,"images":[{"imageUUID":"img"}]}`+"\n"+`{"id":"dog-ta`), 0o644))
+	
+	summary, err := sdk.RunBatchFile(context.Background(), input, output, BatchFileOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, BatchSummary{Total: 5, Skipped: 1, Succeeded: 2, Failed: 2}, summary)
+	
+	f, _ := os.Open(output)
+	results, err := ReadBatchResults(f)
+	f.Close()
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	
+	// Everything ran, only the failed tasks are retried
+	summary, err = sdk.RunBatchFile(context.Background(), input, output, BatchFileOptions{RetryFailed: true})
+	assert.NoError(t, err)
+	assert.Equal(t, BatchSummary{Total: 5, Skipped: 3, Failed: 2}, summary)
+	assert.Len(t, receivedFake[NewTaskReq](server, NewTask), 2)
+}
+
+func TestRunBatchCancelled(t *testing.T) {
+	sdk := newFakeServer().answerImages(2).sdk()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	
+	out := &bytes.Buffer{}
+	_, err := sdk.RunBatch(ctx, strings.NewReader(batchInput), out, BatchOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, out.String())
+}
+
+func TestRunBatchTimeout(t *testing.T) {
+	// The server never answers
+	sdk := newFakeServer().sdk()
+	sdk.timeout = 20 * time.Millisecond
+	
+	out := &bytes.Buffer{}
+	summary, err := sdk.RunBatch(context.Background(), strings.NewReader(strings.Repeat(`{"promptText":"A cat"}`+"\n", 5)), out, BatchOptions{
+		Concurrency: 5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, summary.Failed)
+	
+	// Timed out tasks don't keep waiting for their images
+	assert.Equal(t, 0, sdk.dispatcher().pending())
+}
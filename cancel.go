@@ -0,0 +1,126 @@
+package runware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+type canceller interface {
+	Cancel()
+}
+
+// Cancel stops a running task. Its pending request returns ErrRequestCancelled, late frames
+// of the task are dropped and the abort event is sent to the server when configured
+func (sdk *SDK) Cancel(ctx context.Context, taskUUID string) error {
+	if taskUUID == "" {
+		return fmt.Errorf("%w:[%s]", ErrFieldRequired, "taskUUID")
+	}
+	
+	sdk.tasksMu.Lock()
+	task, ok := sdk.tasks[taskUUID]
+	sdk.tasksMu.Unlock()
+	
+	// Cancelling the call abandons the task
+	if ok {
+		task.Cancel()
+		return nil
+	}
+	
+	return sdk.abandonTask(ctx, taskUUID)
+}
+
+// registerTask keeps the pending call, SDK.Close cancels it. Calls with a task UUID can be cancelled with SDK.Cancel
+func (sdk *SDK) registerTask(taskUUID string, task canceller) {
+	sdk.tasksMu.Lock()
+	defer sdk.tasksMu.Unlock()
+	
+	if sdk.calls == nil {
+		sdk.calls = make(map[canceller]struct{})
+	}
+	sdk.calls[task] = struct{}{}
+	
+	if taskUUID == "" {
+		return
+	}
+	if sdk.tasks == nil {
+		sdk.tasks = make(map[string]canceller)
+	}
+	sdk.tasks[taskUUID] = task
+}
+
+func (sdk *SDK) unregisterTask(taskUUID string, task canceller) {
+	sdk.tasksMu.Lock()
+	defer sdk.tasksMu.Unlock()
+	
+	delete(sdk.calls, task)
+	if sdk.tasks[taskUUID] == task {
+		delete(sdk.tasks, taskUUID)
+	}
+}
+
+// taskReleaser is implemented by clients counting the tasks in flight, they're told when a task ended
+type taskReleaser interface {
+	releaseTask(taskUUID string)
+}
+
+func (sdk *SDK) releaseTask(taskUUID string) {
+	if taskUUID == "" || !sdk.hasClient() {
+		return
+	}
+	if releaser, ok := sdk.currentClient().(taskReleaser); ok {
+		releaser.releaseTask(taskUUID)
+	}
+}
+
+// abandonTask drops the late frames of the task and notifies the server
+func (sdk *SDK) abandonTask(ctx context.Context, taskUUID string) error {
+	if taskUUID == "" || !sdk.hasClient() {
+		return nil
+	}
+	
+	sdk.dispatcher().abandon(taskUUID)
+	
+	if sdk.abortEvent == "" {
+		return nil
+	}
+	
+	abortReq := Request{
+		Event: sdk.abortEvent,
+		Data: map[string]string{
+			"taskUUID": taskUUID,
+		},
+	}
+	if err := sdk.beforeSend(ctx, &abortReq); err != nil {
+		return sdk.onRequestError(ctx, abortReq, err)
+	}
+	if err := sdk.send(abortReq); err != nil {
+		log.Println("Abort task failed", taskUUID, err)
+		return sdk.onRequestError(ctx, abortReq, err)
+	}
+	
+	return nil
+}
+
+// abandon marks the task frames to be dropped
+func (d *dispatcher) abandon(taskUUID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	now := time.Now()
+	for k, at := range d.abandoned {
+		if now.Sub(at) > pendingCallTTL {
+			delete(d.abandoned, k)
+		}
+	}
+	d.abandoned[taskUUID] = now
+}
+
+func (d *dispatcher) isAbandoned(taskUUID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	_, ok := d.abandoned[taskUUID]
+	return ok
+}
@@ -0,0 +1,63 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImageContextCancel(t *testing.T) {
+	// The server never answers
+	server := newFakeServer()
+	sdk := server.sdk()
+	sdk.abortEvent = "abortTask"
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		assert.Eventually(t, func() bool { return len(receivedFake[NewTaskReq](server, NewTask)) == 1 }, time.Second, time.Millisecond)
+		cancel()
+	}()
+	
+	_, err := sdk.NewImage(ctx, NewTaskReq{
+		TaskUUID:   "task-1",
+		PromptText: "A beautiful landscape",
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	
+	assert.Eventually(t, func() bool { return len(receivedFake[json.RawMessage](server, "abortTask")) == 1 }, time.Second, time.Millisecond)
+	assert.JSONEq(t, `{"taskUUID":"task-1"}`, string(receivedFake[json.RawMessage](server, "abortTask")[0]))
+	assert.True(t, sdk.dispatcher().isAbandoned("task-1"))
+	assert.Equal(t, 0, sdk.dispatcher().pending())
+	assert.Empty(t, sdk.tasks)
+}
+
+func TestSDKCancel(t *testing.T) {
+	// The server never answers
+	server := newFakeServer()
+	sdk := server.sdk()
+	sdk.abortEvent = "abortTask"
+	
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		
+		_, err := sdk.NewImage(context.Background(), NewTaskReq{
+			TaskUUID:   "task-1",
+			PromptText: "A beautiful landscape",
+		})
+		assert.ErrorIs(t, err, ErrRequestCancelled)
+	}()
+	
+	assert.Eventually(t, func() bool { return len(receivedFake[NewTaskReq](server, NewTask)) == 1 }, time.Second, time.Millisecond)
+	assert.NoError(t, sdk.Cancel(context.Background(), "task-1"))
+	wg.Wait()
+	
+	assert.Eventually(t, func() bool {
+		return len(receivedFake[json.RawMessage](server, "abortTask")) == 1
+	}, time.Second, time.Millisecond, "abort event not sent")
+}
@@ -0,0 +1,150 @@
+package runware
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const defaultCaptionParallelism = 4
+
+var captionExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+}
+
+// CaptionImage uploads the image and returns its captions
+func (sdk *SDK) CaptionImage(ctx context.Context, data []byte) ([]string, error) {
+	uploaded, err := sdk.UploadImageBytes(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	
+	resp, err := sdk.ImageToText(ctx, NewReverseImageClipReq{
+		ImageUUID: uploaded.NewImageUUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	captions := make([]string, 0, len(resp.Texts))
+	for _, text := range resp.Texts {
+		captions = append(captions, text.Text)
+	}
+	return captions, nil
+}
+
+// CaptionReader reads an image and returns its captions
+func (sdk *SDK) CaptionReader(ctx context.Context, r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.CaptionImage(ctx, data)
+}
+
+// CaptionFile reads an image file and returns its captions
+func (sdk *SDK) CaptionFile(ctx context.Context, path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.CaptionImage(ctx, data)
+}
+
+// CaptionDirOptions options of CaptionDir
+type CaptionDirOptions struct {
+	// Parallelism max images captioned at once, defaults to 4
+	Parallelism int
+	// Extensions of the captioned files, defaults to .jpg, .jpeg, .png and .webp
+	Extensions []string
+}
+
+// CaptionResult captions of one file
+type CaptionResult struct {
+	Path     string
+	Captions []string
+	Err      error
+}
+
+// CaptionDir captions the images of a directory concurrently. Results are ordered by file name,
+// each with its own error
+func (sdk *SDK) CaptionDir(ctx context.Context, dir string, opts CaptionDirOptions) ([]CaptionResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	
+	extensions := captionExtensions
+	if len(opts.Extensions) > 0 {
+		extensions = make(map[string]bool, len(opts.Extensions))
+		for _, ext := range opts.Extensions {
+			extensions["."+strings.TrimPrefix(strings.ToLower(ext), ".")] = true
+		}
+	}
+	
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = defaultCaptionParallelism
+	}
+	
+	var results []CaptionResult
+	for _, entry := range entries {
+		if entry.IsDir() || !extensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		results = append(results, CaptionResult{Path: filepath.Join(dir, entry.Name())})
+	}
+	
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, parallelism)
+	)
+	for idx := range results {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[idx].Err = ctx.Err()
+			continue
+		}
+		
+		wg.Add(1)
+		go func(res *CaptionResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res.Captions, res.Err = sdk.CaptionFile(ctx, res.Path)
+		}(&results[idx])
+	}
+	wg.Wait()
+	
+	return results, nil
+}
+
+// UploadImageBytes uploads an image as base64 data URI, its format is detected from its header
+func (sdk *SDK) UploadImageBytes(ctx context.Context, data []byte) (*NewImageUploadResp, error) {
+	imageBase64, err := encodeImageBase64(data)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ImageUpload(ctx, NewImageUploadReq{
+		ImageBase64: imageBase64,
+	})
+}
+
+func encodeImageBase64(data []byte) (string, error) {
+	format, err := decodeImage(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data:image/%s;base64,%s", format, base64.StdEncoding.EncodeToString(data)), nil
+}
@@ -0,0 +1,57 @@
+package runware
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+var testPNG = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00, 0x00}
+
+func TestCaptionImage(t *testing.T) {
+	sdk := newFakeServer().answerUploads().answerCaptions(0).sdk()
+	
+	captions, err := sdk.CaptionImage(context.Background(), testPNG)
+	assert.NoError(t, err)
+	assert.Len(t, captions, 1)
+	assert.Contains(t, captions[0], "caption of img-")
+	
+	_, err = sdk.CaptionImage(context.Background(), []byte("not an image"))
+	assert.ErrorIs(t, err, ErrImageUnsupported)
+	
+	_, err = sdk.CaptionFile(context.Background(), filepath.Join(t.TempDir(), "missing.png"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCaptionDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.png", "b.PNG", "c.jpg", "d.png", "e.webp"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), testPNG, 0o644))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skip"), 0o644))
+	
+	server := newFakeServer().answerUploads().answerCaptions(20 * time.Millisecond)
+	sdk := server.sdk()
+	
+	results, err := sdk.CaptionDir(context.Background(), dir, CaptionDirOptions{Parallelism: 2})
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.Equal(t, filepath.Join(dir, "a.png"), results[0].Path)
+	for _, res := range results {
+		assert.NoError(t, res.Err)
+		assert.Len(t, res.Captions, 1)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&server.maxInFlight), int32(2))
+	
+	results, err = sdk.CaptionDir(context.Background(), dir, CaptionDirOptions{Extensions: []string{"webp"}})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	
+	_, err = sdk.CaptionDir(context.Background(), filepath.Join(dir, "missing"), CaptionDirOptions{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+func runBatch(ctx context.Context, a *app, args []string) error {
+	var (
+		input  string
+		output string
+		opts   runware.BatchFileOptions
+		quiet  bool
+	)
+	
+	fs := a.flagSet("batch")
+	fs.StringVar(&input, "input", "", "JSONL file with one NewTaskReq per line, with an optional `id`")
+	fs.StringVar(&output, "output", "", "JSONL file the results are appended to, completed tasks are skipped on resume")
+	fs.IntVar(&opts.Concurrency, "parallel", 4, "tasks running at once")
+	fs.BoolVar(&opts.RetryFailed, "retry-failed", false, "run again the tasks that failed in a previous run")
+	fs.BoolVar(&quiet, "quiet", false, "don't report the progress on stderr")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if input == "" || output == "" {
+		return fmt.Errorf("-input and -output are required")
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	if !quiet {
+		opts.OnResult = func(res runware.BatchResult) {
+			status := "ok"
+			if res.Error != "" {
+				status = res.Error
+			}
+			fmt.Fprintf(a.stderr, "line %d [%s] %s\n", res.Line, res.ID, status)
+		}
+	}
+	
+	summary, err := sdk.RunBatchFile(ctx, input, output, opts)
+	if printErr := a.print(summary, []string{"TOTAL", "SKIPPED", "SUCCEEDED", "FAILED"}, [][]string{{
+		strconv.Itoa(summary.Total),
+		strconv.Itoa(summary.Skipped),
+		strconv.Itoa(summary.Succeeded),
+		strconv.Itoa(summary.Failed),
+	}}); printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return err
+	}
+	
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d tasks failed, see %s", summary.Failed, summary.Total, output)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+func runEnhancePrompt(ctx context.Context, a *app, args []string) error {
+	var (
+		req        runware.NewPromptEnhanceReq
+		prompt     string
+		promptFile string
+		language   runware.Language
+	)
+	
+	fs := a.flagSet("enhance-prompt")
+	fs.StringVar(&prompt, "prompt", "", "prompt text, `-` reads one prompt per line from stdin")
+	fs.StringVar(&promptFile, "prompt-file", "", "file with one prompt per line")
+	fs.IntVar(&req.PromptVersions, "versions", 3, "enhanced versions per prompt, 1-5")
+	fs.IntVar(&req.PromptMaxLength, "max-length", 0, "max length of the enhanced prompts, up to 380")
+	fs.Var(&languageFlag{lang: &language}, "language", "prompt language code, e.g. es")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	
+	if language != "" {
+		req.PromptLanguageId = &language
+	}
+	
+	prompts, err := readPrompts(prompt, promptFile, a.stdin)
+	if err != nil {
+		return err
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	type enhanceOutput struct {
+		Prompt   string   `json:"prompt"`
+		Enhanced []string `json:"enhanced"`
+	}
+	var (
+		out  = make([]enhanceOutput, 0, len(prompts))
+		rows [][]string
+	)
+	for _, p := range prompts {
+		r := req
+		r.PromptText = p
+		resp, err := sdk.PromptEnhancer(ctx, r)
+		if err != nil {
+			return fmt.Errorf("enhance %q: %w", p, err)
+		}
+		
+		o := enhanceOutput{Prompt: p}
+		for idx, text := range resp.Texts {
+			o.Enhanced = append(o.Enhanced, text.Text)
+			rows = append(rows, []string{p, strconv.Itoa(idx + 1), text.Text})
+		}
+		out = append(out, o)
+	}
+	
+	return a.print(out, []string{"PROMPT", "VERSION", "ENHANCED"}, rows)
+}
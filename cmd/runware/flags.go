@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+// loraFlag repeatable `-lora modelId[:weight]` flag
+type loraFlag []runware.Lora
+
+func (f *loraFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *loraFlag) Set(v string) error {
+	lora := runware.Lora{ModelID: v, Weight: 1}
+	if i := strings.LastIndex(v, ":"); i > 0 {
+		if weight, err := strconv.ParseFloat(v[i+1:], 64); err == nil {
+			lora.ModelID, lora.Weight = v[:i], weight
+		}
+	}
+	*f = append(*f, lora)
+	return nil
+}
+
+// controlNetFlag repeatable `-controlnet '{"preprocessor":"canny","guideImageUUID":"..."}'` flag
+type controlNetFlag []runware.ControlNet
+
+func (f *controlNetFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *controlNetFlag) Set(v string) error {
+	var cn runware.ControlNet
+	if err := json.Unmarshal([]byte(v), &cn); err != nil {
+		return fmt.Errorf("invalid controlnet JSON: %w", err)
+	}
+	*f = append(*f, cn)
+	return nil
+}
+
+// languageFlag `-language es` flag
+type languageFlag struct {
+	lang *runware.Language
+}
+
+func (f *languageFlag) String() string {
+	if f.lang == nil {
+		return ""
+	}
+	return f.lang.Code()
+}
+
+func (f *languageFlag) Set(v string) error {
+	lang, err := runware.LanguageFromCode(v)
+	if err != nil {
+		return err
+	}
+	*f.lang = lang
+	return nil
+}
+
+var nsfwPolicies = map[string]runware.NSFWPolicy{
+	"allow": runware.NSFWAllow,
+	"flag":  runware.NSFWFlag,
+	"drop":  runware.NSFWDrop,
+	"fail":  runware.NSFWFail,
+}
+
+// nsfwFlag `-nsfw drop` flag
+type nsfwFlag struct {
+	policy *runware.NSFWPolicy
+}
+
+func (f *nsfwFlag) String() string {
+	if f.policy == nil {
+		return ""
+	}
+	for name, policy := range nsfwPolicies {
+		if policy == *f.policy {
+			return name
+		}
+	}
+	return ""
+}
+
+func (f *nsfwFlag) Set(v string) error {
+	policy, ok := nsfwPolicies[strings.ToLower(v)]
+	if !ok {
+		return fmt.Errorf("unknown nsfw policy %q, allow, flag, drop or fail", v)
+	}
+	*f.policy = policy
+	return nil
+}
+
+// readPrompts returns the prompt, or one prompt per line of the file. `-` reads them from stdin
+func readPrompts(prompt, promptFile string, stdin io.Reader) ([]string, error) {
+	var r io.Reader
+	switch {
+	case prompt != "" && promptFile != "":
+		return nil, fmt.Errorf("-prompt and -prompt-file are exclusive")
+	case prompt == "-" || promptFile == "-":
+		r = stdin
+	case promptFile != "":
+		f, err := os.Open(promptFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	case prompt != "":
+		return []string{prompt}, nil
+	default:
+		return nil, fmt.Errorf("a prompt is required, use -prompt or -prompt-file")
+	}
+	
+	var prompts []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			prompts = append(prompts, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no prompts found")
+	}
+	return prompts, nil
+}
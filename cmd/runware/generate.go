@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+type generateOptions struct {
+	req        runware.NewTaskReq
+	prompt     string
+	promptFile string
+	outDir     string
+	language   runware.Language
+}
+
+// generateFlags registers the NewTaskReq flags
+func (a *app) generateFlags() (*generateOptions, func(args []string) error) {
+	opts := &generateOptions{}
+	req := &opts.req
+	
+	fs := a.flagSet("generate")
+	fs.StringVar(&opts.prompt, "prompt", "", "prompt text, `-` reads one prompt per line from stdin")
+	fs.StringVar(&opts.promptFile, "prompt-file", "", "file with one prompt per line")
+	fs.StringVar(&opts.outDir, "out", "", "directory the images are saved to")
+	fs.StringVar(&req.TaskUUID, "task-uuid", "", "task UUID, generated when empty")
+	fs.StringVar(&req.ImageInitiatorUUID, "image-initiator-uuid", "", "image UUID for image-to-image")
+	fs.StringVar(&req.NegativePrompt, "negative-prompt", "", "negative prompt")
+	fs.IntVar(&req.NumberResults, "results", 1, "number of images")
+	fs.StringVar(&req.ModelId, "model", "", "model ID, e.g. "+runware.ModelSDXL.String())
+	fs.IntVar(&req.SizeId, "size-id", 0, "size preset")
+	fs.IntVar(&req.Width, "width", 0, "custom width, multiple of 64")
+	fs.IntVar(&req.Height, "height", 0, "custom height, multiple of 64")
+	fs.IntVar(&req.TaskType, "task-type", 0, "task type, evaluated when empty")
+	fs.Var(&languageFlag{lang: &opts.language}, "language", "prompt language code, e.g. es")
+	fs.IntVar(&req.Offset, "offset", 0, "results offset")
+	fs.Var((*loraFlag)(&req.Lora), "lora", "LoRA as modelId[:weight], repeatable")
+	fs.Var((*controlNetFlag)(&req.ControlNet), "controlnet", "ControlNet as JSON object, repeatable")
+	fs.Int64Var(&req.Seed, "seed", 0, "seed, random when empty")
+	fs.IntVar(&req.Steps, "steps", 0, "inference steps")
+	fs.Float64Var(&req.CFGScale, "cfg-scale", 0, "guidance scale")
+	fs.StringVar((*string)(&req.Scheduler), "scheduler", "", "scheduler, e.g. "+string(runware.SchedulerEuler))
+	fs.IntVar(&req.ClipSkip, "clip-skip", 0, "CLIP layers to skip")
+	fs.Float64Var(&req.Strength, "strength", 0, "image-to-image strength")
+	fs.StringVar((*string)(&req.OutputFormat), "output-format", "", "JPG, PNG or WEBP")
+	fs.IntVar(&req.OutputQuality, "output-quality", 0, "output quality")
+	fs.Var(&nsfwFlag{policy: &req.NSFWPolicy}, "nsfw", "NSFW policy, allow, flag, drop or fail")
+	
+	return opts, func(args []string) error {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if opts.language != "" {
+			req.PromptLanguageId = &opts.language
+		}
+		return nil
+	}
+}
+
+type generateResult struct {
+	Prompt string               `json:"prompt"`
+	Resp   *runware.NewTaskResp `json:"response,omitempty"`
+	Paths  []string             `json:"paths,omitempty"`
+	Error  string               `json:"error,omitempty"`
+}
+
+func runGenerate(ctx context.Context, a *app, args []string) error {
+	opts, parse := a.generateFlags()
+	if err := parse(args); err != nil {
+		return err
+	}
+	
+	prompts, err := readPrompts(opts.prompt, opts.promptFile, a.stdin)
+	if err != nil {
+		return err
+	}
+	if len(prompts) > 1 && opts.req.TaskUUID != "" {
+		return fmt.Errorf("-task-uuid can't be used with several prompts")
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	var (
+		failed int
+		rows   [][]string
+	)
+	for _, prompt := range prompts {
+		res := a.generate(ctx, sdk, opts, prompt)
+		if res.Error != "" {
+			failed++
+		}
+		
+		// JSON results are streamed, the table is printed once all prompts ran
+		if a.format == formatJSON {
+			if err = a.print(res, nil, nil); err != nil {
+				return err
+			}
+		}
+		rows = append(rows, res.rows()...)
+	}
+	
+	if a.format == formatTable {
+		if err = a.print(nil, []string{"PROMPT", "IMAGE UUID", "SEED", "NSFW", "IMAGE"}, rows); err != nil {
+			return err
+		}
+	}
+	
+	if failed > 0 {
+		return fmt.Errorf("%d of %d prompts failed", failed, len(prompts))
+	}
+	return nil
+}
+
+func (a *app) generate(ctx context.Context, sdk *runware.SDK, opts *generateOptions, prompt string) generateResult {
+	req := opts.req
+	req.PromptText = prompt
+	
+	res := generateResult{Prompt: prompt}
+	resp, err := sdk.NewImage(ctx, req)
+	res.Resp = resp
+	// The CLI doesn't wait beyond -timeout, the remaining images are given up
+	if resp != nil && resp.Pending != nil {
+		resp.Pending.Cancel()
+	}
+	if err != nil {
+		res.Error = err.Error()
+		// The images received before the timeout are saved all the same, the prompt still fails
+		if resp == nil || !errors.Is(err, runware.ErrRequestTimeout) {
+			return res
+		}
+	}
+	
+	if opts.outDir != "" && resp != nil {
+		format := req.OutputFormat
+		if resp.Manifest != nil {
+			format = resp.Manifest.Request.OutputFormat
+		}
+		res.Paths, err = saveImages(ctx, opts.outDir, resp.Images, format)
+		if err != nil && res.Error == "" {
+			res.Error = err.Error()
+		}
+	}
+	return res
+}
+
+func (res generateResult) rows() [][]string {
+	var rows [][]string
+	if res.Error != "" {
+		rows = append(rows, []string{res.Prompt, "", "", "", res.Error})
+	}
+	if res.Resp == nil {
+		return rows
+	}
+	
+	for idx, img := range res.Resp.Images {
+		location := img.ImageSrc
+		if idx < len(res.Paths) {
+			location = res.Paths[idx]
+		}
+		rows = append(rows, []string{res.Prompt, img.ImageUUID, strconv.FormatInt(img.Seed, 10), strconv.FormatBool(img.BNSFWContent), location})
+	}
+	return rows
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+// imageInput an image given by path, uploaded first, or by UUID
+type imageInput struct {
+	path string
+	uuid string
+}
+
+func (in *imageInput) flags(fs *flag.FlagSet) {
+	fs.StringVar(&in.path, "image", "", "image file, uploaded first")
+	fs.StringVar(&in.uuid, "image-uuid", "", "UUID of an uploaded image")
+}
+
+// resolve returns the image UUID, uploading the image file when needed
+func (in *imageInput) resolve(ctx context.Context, sdk *runware.SDK) (string, error) {
+	switch {
+	case in.path != "" && in.uuid != "":
+		return "", fmt.Errorf("-image and -image-uuid are exclusive")
+	case in.uuid != "":
+		return in.uuid, nil
+	case in.path != "":
+		uploaded, err := uploadFile(ctx, sdk, in.path)
+		if err != nil {
+			return "", err
+		}
+		return uploaded.NewImageUUID, nil
+	default:
+		return "", fmt.Errorf("an image is required, use -image or -image-uuid")
+	}
+}
+
+// uploadFile uploads the image file as base64 data URI
+func uploadFile(ctx context.Context, sdk *runware.SDK, path string) (*runware.NewImageUploadResp, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.UploadImageBytes(ctx, data)
+}
+
+type uploadResult struct {
+	Path      string `json:"path"`
+	ImageUUID string `json:"imageUUID,omitempty"`
+	ImageSrc  string `json:"imageSrc,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func runUpload(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("upload")
+	fs.Usage = func() {
+		fmt.Fprintln(a.stderr, "Usage: runware upload [flags] <image>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("at least one image is required")
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	var (
+		results = make([]uploadResult, 0, fs.NArg())
+		rows    [][]string
+		failed  int
+	)
+	for _, p := range fs.Args() {
+		res := uploadResult{Path: p}
+		uploaded, err := uploadFile(ctx, sdk, p)
+		if err == nil {
+			res.ImageUUID, res.ImageSrc = uploaded.NewImageUUID, uploaded.NewImageSrc
+		}
+		if err != nil {
+			res.Error = err.Error()
+			failed++
+		}
+		
+		results = append(results, res)
+		rows = append(rows, []string{res.Path, res.ImageUUID, res.Error})
+	}
+	
+	if err = a.print(results, []string{"PATH", "IMAGE UUID", "ERROR"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", failed, len(results))
+	}
+	return nil
+}
+
+func runUpscale(ctx context.Context, a *app, args []string) error {
+	var (
+		in     imageInput
+		req    runware.NewUpscaleGanReq
+		outDir string
+	)
+	
+	fs := a.flagSet("upscale")
+	in.flags(fs)
+	fs.IntVar(&req.UpscaleFactor, "factor", 2, "upscale factor")
+	fs.Var(&nsfwFlag{policy: &req.NSFWPolicy}, "nsfw", "NSFW policy, allow, flag, drop or fail")
+	fs.StringVar(&outDir, "out", "", "directory the images are saved to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	if req.ImageUUID, err = in.resolve(ctx, sdk); err != nil {
+		return err
+	}
+	
+	resp, err := sdk.ImageUpscale(ctx, req)
+	if err != nil {
+		return err
+	}
+	
+	paths := make([]string, len(resp.Images))
+	if outDir != "" {
+		if paths, err = saveImages(ctx, outDir, resp.Images, ""); err != nil {
+			return err
+		}
+	}
+	
+	rows := make([][]string, 0, len(resp.Images))
+	for idx, img := range resp.Images {
+		rows = append(rows, []string{img.ImageUUID, img.ImageSrc, paths[idx]})
+	}
+	return a.print(resp, []string{"IMAGE UUID", "IMAGE", "PATH"}, rows)
+}
+
+func runCaption(ctx context.Context, a *app, args []string) error {
+	var (
+		dir         string
+		parallelism int
+	)
+	
+	fs := a.flagSet("caption")
+	fs.StringVar(&dir, "dir", "", "directory of images to caption")
+	fs.IntVar(&parallelism, "parallel", 4, "images captioned at once with -dir")
+	fs.Usage = func() {
+		fmt.Fprintln(a.stderr, "Usage: runware caption [flags] <image>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if dir == "" && fs.NArg() == 0 {
+		return fmt.Errorf("images or -dir are required")
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	var results []runware.CaptionResult
+	if dir != "" {
+		if results, err = sdk.CaptionDir(ctx, dir, runware.CaptionDirOptions{Parallelism: parallelism}); err != nil {
+			return err
+		}
+	}
+	for _, p := range fs.Args() {
+		res := runware.CaptionResult{Path: p}
+		res.Captions, res.Err = sdk.CaptionFile(ctx, p)
+		results = append(results, res)
+	}
+	
+	type captionOutput struct {
+		Path     string   `json:"path"`
+		Captions []string `json:"captions,omitempty"`
+		Error    string   `json:"error,omitempty"`
+	}
+	var (
+		out    = make([]captionOutput, 0, len(results))
+		rows   [][]string
+		failed int
+	)
+	for _, res := range results {
+		o := captionOutput{Path: res.Path, Captions: res.Captions}
+		if res.Err != nil {
+			o.Error = res.Err.Error()
+			failed++
+		}
+		out = append(out, o)
+		rows = append(rows, []string{o.Path, strings.Join(o.Captions, " | "), o.Error})
+	}
+	
+	if err = a.print(out, []string{"PATH", "CAPTION", "ERROR"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d captions failed", failed, len(results))
+	}
+	return nil
+}
+
+func runControlNetPreprocess(ctx context.Context, a *app, args []string) error {
+	var (
+		in     imageInput
+		req    runware.NewControlNetsReq
+		outDir string
+	)
+	
+	fs := a.flagSet("controlnet-preprocess")
+	in.flags(fs)
+	fs.StringVar(&req.PreProcessorType, "preprocessor", "", "preprocessor type, e.g. canny")
+	fs.IntVar(&req.Width, "width", 0, "output width")
+	fs.IntVar(&req.Height, "height", 0, "output height")
+	fs.IntVar(&req.LowThresholdCanny, "low-threshold", 0, "canny low threshold")
+	fs.IntVar(&req.HighThresholdCanny, "high-threshold", 0, "canny high threshold")
+	fs.Var(&nsfwFlag{policy: &req.NSFWPolicy}, "nsfw", "NSFW policy, allow, flag, drop or fail")
+	fs.StringVar(&outDir, "out", "", "directory the guide image is saved to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	
+	sdk, err := a.sdk()
+	if err != nil {
+		return err
+	}
+	defer sdk.Close()
+	
+	if req.GuideImageUUID, err = in.resolve(ctx, sdk); err != nil {
+		return err
+	}
+	
+	resp, err := sdk.NewControlNets(ctx, req)
+	if err != nil && !(resp != nil && errors.Is(err, runware.ErrRequestTimeout)) {
+		return err
+	}
+	// The guide image received before the timeout is printed, the command still fails
+	timeoutErr := err
+	
+	var path string
+	if outDir != "" && resp.NewImageSrc != "" {
+		paths, err := saveImages(ctx, outDir, []runware.Image{{ImageUUID: resp.NewImageUUID, ImageSrc: resp.NewImageSrc}}, "")
+		if err != nil {
+			return err
+		}
+		path = paths[0]
+	}
+	
+	nsfw := ""
+	if resp.NNsfwContent != nil {
+		nsfw = strconv.FormatBool(*resp.NNsfwContent)
+	}
+	if err = a.print(resp, []string{"IMAGE UUID", "IMAGE", "NSFW", "PATH"}, [][]string{
+		{resp.NewImageUUID, resp.NewImageSrc, nsfw, path},
+	}); err != nil {
+		return err
+	}
+	return timeoutErr
+}
@@ -0,0 +1,121 @@
+// Command runware runs Runware tasks from the command line
+//
+//	runware generate -prompt "A cat in space" -results 4 -out ./images
+//	runware caption ./photo.png
+//	cat prompts.txt | runware generate -prompt - -format table
+//	runware batch -input tasks.jsonl -output results.jsonl -parallel 8
+//
+// The API key is read from the -api-key flag or the RUNWARE_API environment variable
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{name: "generate", usage: "Generate images from prompts", run: runGenerate},
+	{name: "upload", usage: "Upload images and print their UUID", run: runUpload},
+	{name: "upscale", usage: "Upscale an image", run: runUpscale},
+	{name: "caption", usage: "Caption images or a directory of images", run: runCaption},
+	{name: "enhance-prompt", usage: "Enhance prompts into several versions", run: runEnhancePrompt},
+	{name: "controlnet-preprocess", usage: "Preprocess a ControlNet guide image", run: runControlNetPreprocess},
+	{name: "batch", usage: "Run the image tasks of a JSONL file, resumable", run: runBatch},
+}
+
+// app holds the IO and the SDK options shared by all commands
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	
+	apiKey  string
+	timeout time.Duration
+	format  string
+	
+	// newSDK connects to the API, replaced in tests
+	newSDK func(cfg runware.SDKConfig) (*runware.SDK, error)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	
+	a := &app{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		newSDK: runware.NewSDK,
+	}
+	if err := a.run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "runware:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		a.usage()
+		return flag.ErrHelp
+	}
+	
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, a, args[1:])
+		}
+	}
+	
+	a.usage()
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "Usage: runware <command> [flags]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(a.stderr, "  %-22s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Run `runware <command> -h` for the command flags")
+}
+
+// flagSet returns the flags of a command with the shared ones
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.apiKey, "api-key", os.Getenv("RUNWARE_API"), "API key, defaults to $RUNWARE_API")
+	fs.DurationVar(&a.timeout, "timeout", 0, "request timeout, defaults to the SDK one")
+	fs.StringVar(&a.format, "format", formatJSON, "output format, json or table")
+	return fs
+}
+
+func (a *app) sdk() (*runware.SDK, error) {
+	if a.format != formatJSON && a.format != formatTable {
+		return nil, fmt.Errorf("unknown format %q", a.format)
+	}
+	if a.apiKey == "" {
+		return nil, runware.ErrApiKeyRequired
+	}
+	
+	return a.newSDK(runware.SDKConfig{
+		APIKey:         a.apiKey,
+		RequestTimeout: a.timeout,
+	})
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	
+	runware "github.com/Runware/sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient answers the connection and the image tasks, images are served by imageServer
+type fakeClient struct {
+	incoming    chan []byte
+	imageServer string
+	// partial answers a single image whatever the number of results
+	partial bool
+}
+
+func (c *fakeClient) APIKey() string             { return "key" }
+func (c *fakeClient) Connected() bool            { return true }
+func (c *fakeClient) Close() error               { return nil }
+func (c *fakeClient) Listen() chan []byte        { return c.incoming }
+func (c *fakeClient) Reconnected() chan struct{} { return nil }
+
+func (c *fakeClient) Send(b []byte) error {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	
+	if _, ok := msg[runware.NewConnection]; ok {
+		c.incoming <- []byte(`{"newConnectionSessionUUID":{"connectionSessionUUID":"session"}}`)
+		return nil
+	}
+	// Preprocessing is never answered
+	if _, ok := msg[runware.NewPreProcessControlNet]; ok {
+		return nil
+	}
+	
+	var req runware.NewTaskReq
+	if err := json.Unmarshal(msg[runware.NewTask], &req); err != nil {
+		return err
+	}
+	images := make([]runware.Image, req.NumberResults)
+	if c.partial {
+		images = images[:1]
+	}
+	for i := range images {
+		uuid := fmt.Sprintf("%s-%d", req.TaskUUID, i)
+		images[i] = runware.Image{ImageUUID: uuid, ImageSrc: c.imageServer + "/" + uuid + ".png", TaskUUID: req.TaskUUID}
+	}
+	resp, _ := json.Marshal(map[string]runware.NewTaskResp{runware.NewImage: {Images: images}})
+	c.incoming <- resp
+	return nil
+}
+
+func newTestApp(stdin string) (*app, *bytes.Buffer) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("image"))
+	}))
+	
+	stdout := &bytes.Buffer{}
+	return &app{
+		stdin:  strings.NewReader(stdin),
+		stdout: stdout,
+		stderr: &bytes.Buffer{},
+		newSDK: func(cfg runware.SDKConfig) (*runware.SDK, error) {
+			cfg.Client = &fakeClient{incoming: make(chan []byte, 10), imageServer: server.URL}
+			return runware.NewSDK(cfg)
+		},
+	}, stdout
+}
+
+func TestReadPrompts(t *testing.T) {
+	prompts, err := readPrompts("A cat", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A cat"}, prompts)
+	
+	prompts, err = readPrompts("-", "", strings.NewReader("A cat\n\n  A dog  \n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A cat", "A dog"}, prompts)
+	
+	file := filepath.Join(t.TempDir(), "prompts.txt")
+	assert.NoError(t, os.WriteFile(file, []byte("A bird\n"), 0o644))
+	prompts, err = readPrompts("", file, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A bird"}, prompts)
+	
+	_, err = readPrompts("", "", nil)
+	assert.Error(t, err)
+	_, err = readPrompts("A cat", file, nil)
+	assert.Error(t, err)
+}
+
+func TestGenerateFlags(t *testing.T) {
+	a, _ := newTestApp("")
+	opts, parse := a.generateFlags()
+	err := parse([]string{
+		"-prompt", "A cat", "-results", "3", "-model", "runware:100@1", "-seed", "42",
+		"-lora", "civitai:58390@62833:0.8", "-lora", "civitai:1@1",
+		"-controlnet", `{"preprocessor":"canny","guideImageUUID":"img-1","weight":1}`,
+		"-language", "es", "-nsfw", "drop", "-output-format", "PNG",
+	})
+	assert.NoError(t, err)
+	
+	req := opts.req
+	assert.Equal(t, 3, req.NumberResults)
+	assert.Equal(t, "runware:100@1", req.ModelId)
+	assert.Equal(t, int64(42), req.Seed)
+	assert.Equal(t, []runware.Lora{{ModelID: "civitai:58390@62833", Weight: 0.8}, {ModelID: "civitai:1@1", Weight: 1}}, req.Lora)
+	assert.Equal(t, "img-1", req.ControlNet[0].GuideImageUUID)
+	assert.Equal(t, runware.LanguageSpanish, *req.PromptLanguageId)
+	assert.Equal(t, runware.NSFWDrop, req.NSFWPolicy)
+	assert.Equal(t, runware.OutputFormatPNG, req.OutputFormat)
+	
+	_, parse = a.generateFlags()
+	assert.Error(t, parse([]string{"-nsfw", "maybe"}))
+}
+
+func TestRunGenerate(t *testing.T) {
+	a, stdout := newTestApp("A cat\nA dog\n")
+	out := t.TempDir()
+	
+	err := a.run(context.Background(), []string{"generate", "-api-key", "key", "-prompt", "-", "-results", "2", "-format", "table", "-out", out})
+	assert.NoError(t, err)
+	
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.True(t, strings.HasPrefix(lines[0], "PROMPT"))
+	assert.Contains(t, lines[1], "A cat")
+	assert.Contains(t, lines[3], "A dog")
+	
+	saved, _ := filepath.Glob(filepath.Join(out, "*.png"))
+	assert.Len(t, saved, 4)
+}
+
+func TestRunGenerateJSON(t *testing.T) {
+	a, stdout := newTestApp("")
+	
+	err := a.run(context.Background(), []string{"generate", "-api-key", "key", "-prompt", "A cat"})
+	assert.NoError(t, err)
+	
+	var res generateResult
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &res))
+	assert.Equal(t, "A cat", res.Prompt)
+	assert.Len(t, res.Resp.Images, 1)
+}
+
+func TestRunGenerateTimeout(t *testing.T) {
+	a, stdout := newTestApp("")
+	newSDK := a.newSDK
+	a.newSDK = func(cfg runware.SDKConfig) (*runware.SDK, error) {
+		sdk, err := newSDK(cfg)
+		if err == nil {
+			sdk.Client.(*fakeClient).partial = true
+		}
+		return sdk, err
+	}
+	
+	err := a.run(context.Background(), []string{"generate", "-api-key", "key", "-prompt", "A cat", "-results", "2", "-timeout", "50ms"})
+	assert.Error(t, err)
+	
+	var res generateResult
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &res))
+	assert.Contains(t, res.Error, runware.ErrRequestTimeout.Error())
+	assert.Len(t, res.Resp.Images, 1)
+}
+
+func TestRunControlNetPreprocessTimeout(t *testing.T) {
+	a, stdout := newTestApp("")
+	
+	err := a.run(context.Background(), []string{"controlnet-preprocess", "-api-key", "key", "-image-uuid", "img-1", "-preprocessor", "canny", "-timeout", "50ms"})
+	assert.ErrorIs(t, err, runware.ErrRequestTimeout)
+	assert.NotEmpty(t, stdout.String())
+}
+
+func TestRunErrors(t *testing.T) {
+	a, _ := newTestApp("")
+	
+	assert.Error(t, a.run(context.Background(), []string{"unknown"}))
+	assert.ErrorIs(t, a.run(context.Background(), []string{"generate", "-api-key", "", "-prompt", "A cat"}), runware.ErrApiKeyRequired)
+	assert.Error(t, a.run(context.Background(), []string{"generate", "-api-key", "key", "-prompt", "A cat", "-format", "xml"}))
+	assert.Error(t, a.run(context.Background(), []string{"upload", "-api-key", "key"}))
+}
+
+func TestRunBatch(t *testing.T) {
+	a, stdout := newTestApp("")
+	dir := t.TempDir()
+	input := filepath.Join(dir, "tasks.jsonl")
+	output := filepath.Join(dir, "results.jsonl")
+	assert.NoError(t, os.WriteFile(input, []byte(`{"id":"cat","promptText":"A cat","numberResults":1}`+"\n"+`{"promptText":""}`+"\n"), 0o644))
+	
+	err := a.run(context.Background(), []string{"batch", "-api-key", "key", "-input", input, "-output", output})
+	assert.ErrorContains(t, err, "1 of 2 tasks failed")
+	
+	var summary runware.BatchSummary
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &summary))
+	assert.Equal(t, runware.BatchSummary{Total: 2, Succeeded: 1, Failed: 1}, summary)
+	
+	stdout.Reset()
+	err = a.run(context.Background(), []string{"batch", "-api-key", "key", "-input", input, "-output", output})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &summary))
+	assert.Equal(t, runware.BatchSummary{Total: 2, Skipped: 2}, summary)
+}
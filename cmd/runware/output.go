@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	
+	runware "github.com/Runware/sdk-go"
+)
+
+const (
+	formatJSON  = "json"
+	formatTable = "table"
+)
+
+// print writes v as JSON, or the rows as a table
+func (a *app) print(v interface{}, header []string, rows [][]string) error {
+	if a.format == formatJSON {
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// saveImages downloads the images into dir and returns their paths
+func saveImages(ctx context.Context, dir string, images []runware.Image, format runware.OutputFormat) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	
+	paths := make([]string, 0, len(images))
+	for _, img := range images {
+		ext := path.Ext(strings.SplitN(img.ImageSrc, "?", 2)[0])
+		if ext == "" {
+			ext = "." + strings.ToLower(string(runware.OutputFormatJPG))
+			if format != "" {
+				ext = "." + strings.ToLower(string(format))
+			}
+		}
+		
+		p := filepath.Join(dir, img.ImageUUID+ext)
+		if err := download(ctx, img.ImageSrc, p); err != nil {
+			return paths, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+func download(ctx context.Context, src, dst string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return err
+	}
+	
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: %s", src, res.Status)
+	}
+	
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, res.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -1,14 +1,79 @@
 package runware
 
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DialOptions websocket connection settings, used to connect and reconnect
+type DialOptions struct {
+	// Proxy returns the proxy of the connection, defaults to http.ProxyFromEnvironment
+	Proxy func(*http.Request) (*url.URL, error)
+	// TLSConfig e.g. to pin certificates
+	TLSConfig *tls.Config
+	// HandshakeTimeout defaults to 45 seconds
+	HandshakeTimeout time.Duration
+	// Header sent with the handshake, e.g. User-Agent
+	Header http.Header
+	// EnableCompression negotiates permessage-deflate with the server
+	EnableCompression bool
+	ReadBufferSize    int
+	WriteBufferSize   int
+	// MaxMessageSize limits the size of incoming messages in bytes, unlimited when 0
+	MaxMessageSize int64
+}
+
 type RunwareConfig struct {
 	APIKey    string
 	ConnAddr  ConnAddr
 	KeepAlive bool
+	DialOptions
+	// OnLifecycle is called on disconnections, reconnections and close
+	OnLifecycle func(LifecycleEvent)
+}
+
+type PoolConfig struct {
+	RunwareConfig
+	// Size number of sessions, defaults to 2
+	Size int
+	// HealthCheckInterval between replacements of unhealthy sessions, defaults to 10 seconds
+	HealthCheckInterval time.Duration
+	// Dial creates the session clients, defaults to New
+	Dial func(cfg RunwareConfig) (Runware, error)
 }
 
 type SDKConfig struct {
-	APIKey    string
-	ConnAddr  ConnAddr
-	KeepAlive bool
-	Client    Runware
+	APIKey      string
+	ConnAddr    ConnAddr
+	KeepAlive   bool
+	Client      Runware
+	Middlewares []Middleware
+	// RequestTimeout defaults to 30 seconds
+	RequestTimeout time.Duration
+	// AbortEvent is sent with the task UUID when a task is cancelled. When empty cancelled
+	// tasks are only abandoned locally
+	AbortEvent string
+	// NSFWPolicy applied to the returned images, it can be overridden per request
+	NSFWPolicy NSFWPolicy
+	// LanguageDetector detects the language of prompts without one, defaults to ScriptLanguageDetector
+	LanguageDetector LanguageDetector
+	// UploadCache reuses the image UUID of identical uploaded images, disabled when nil
+	UploadCache UploadCache
+	// Transport used when Client is nil, defaults to TransportWebSocket
+	Transport Transport
+	// HTTPClient used by TransportHTTP
+	HTTPClient *http.Client
+	// PoolSize spreads the tasks over several websocket sessions when greater than 1
+	PoolSize int
+	DialOptions
+	// OnLifecycle is called on every connection lifecycle event, see also SDK.Subscribe
+	OnLifecycle func(LifecycleEvent)
+	// LazyConnect defers the connection to the first request
+	LazyConnect bool
+	// ConnectAttempts of lazy connections, defaults to 3
+	ConnectAttempts int
+	// Credentials provide the API key on every connection and reconnection, APIKey is used when nil
+	Credentials CredentialProvider
 }
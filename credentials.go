@@ -0,0 +1,288 @@
+package runware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultCredentialsPollInterval = 10 * time.Second
+
+// CredentialProvider returns the API key, it's consulted on every connection and reconnection
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialWatcher is implemented by providers notifying key changes, the SDK rotates its key on every change
+type CredentialWatcher interface {
+	Changed() <-chan struct{}
+}
+
+// StaticCredentials is a fixed API key
+type StaticCredentials string
+
+func (c StaticCredentials) APIKey(context.Context) (string, error) {
+	if c == "" {
+		return "", ErrApiKeyRequired
+	}
+	return string(c), nil
+}
+
+// EnvCredentials reads the API key from the environment variable of the given name
+type EnvCredentials string
+
+func (c EnvCredentials) APIKey(context.Context) (string, error) {
+	apiKey := os.Getenv(string(c))
+	if apiKey == "" {
+		return "", fmt.Errorf("%w:[%s]", ErrApiKeyRequired, string(c))
+	}
+	return apiKey, nil
+}
+
+// CredentialFunc returns the API key, e.g. from a secret manager
+type CredentialFunc func(ctx context.Context) (string, error)
+
+func (f CredentialFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// FileCredentials reads the API key from a file, e.g. a mounted secret, and watches it for changes
+type FileCredentials struct {
+	path    string
+	changed chan struct{}
+	done    chan struct{}
+	
+	mu        sync.Mutex
+	apiKey    string
+	modTime   time.Time
+	closeOnce sync.Once
+}
+
+// NewFileCredentials reads the API key from path, the file is checked for changes every interval,
+// defaults to 10 seconds
+func NewFileCredentials(path string, interval time.Duration) (*FileCredentials, error) {
+	if interval <= 0 {
+		interval = defaultCredentialsPollInterval
+	}
+	
+	c := &FileCredentials{
+		path:    path,
+		changed: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	
+	go c.watch(interval)
+	
+	return c, nil
+}
+
+func (c *FileCredentials) APIKey(context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	return c.apiKey, nil
+}
+
+// Changed receives when the key in the file changed
+func (c *FileCredentials) Changed() <-chan struct{} {
+	return c.changed
+}
+
+// Close stops watching the file
+func (c *FileCredentials) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return nil
+}
+
+func (c *FileCredentials) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+		
+		changed, err := c.reload()
+		if err != nil {
+			// Secrets are often replaced by a rename, keep the last key until the file is back
+			log.Println("Cannot reload credentials", err)
+			continue
+		}
+		if changed {
+			select {
+			case c.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// reload reads the file when modified, it reports whether the key changed
+func (c *FileCredentials) reload() (bool, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return false, fmt.Errorf("%w:[%s]", ErrCredentials, err.Error())
+	}
+	
+	c.mu.Lock()
+	modified := !info.ModTime().Equal(c.modTime)
+	c.mu.Unlock()
+	if !modified {
+		return false, nil
+	}
+	
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		return false, fmt.Errorf("%w:[%s]", ErrCredentials, err.Error())
+	}
+	apiKey := string(bytes.TrimSpace(b))
+	if apiKey == "" {
+		return false, fmt.Errorf("%w:[%s]", ErrApiKeyRequired, c.path)
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	changed := apiKey != c.apiKey
+	c.apiKey = apiKey
+	c.modTime = info.ModTime()
+	return changed, nil
+}
+
+// sessionAuthenticator is implemented by clients managing their sessions, they re-authenticate them themselves
+type sessionAuthenticator interface {
+	Reauthenticate(ctx context.Context, apiKey string) error
+}
+
+// apiKeySetter is implemented by clients keeping the key for their reconnections
+type apiKeySetter interface {
+	SetAPIKey(apiKey string)
+}
+
+// RotateAPIKey re-authenticates the session with a new key, the requests in flight are not interrupted.
+// With a dynamic CredentialProvider the new key is used until the provider returns another key
+func (sdk *SDK) RotateAPIKey(ctx context.Context, apiKey string) error {
+	if apiKey == "" {
+		return ErrApiKeyRequired
+	}
+	if err := sdk.ensureConnected(ctx); err != nil {
+		return err
+	}
+	
+	// Rotations are sent one at a time, reconnections don't wait for them
+	sdk.rotateMu.Lock()
+	defer sdk.rotateMu.Unlock()
+	
+	sdk.credentialsMu.Lock()
+	credentials := sdk.credentials
+	sdk.credentialsMu.Unlock()
+	
+	_, static := credentials.(StaticCredentials)
+	var providerKey string
+	if credentials != nil && !static {
+		var err error
+		if providerKey, err = credentials.APIKey(ctx); err != nil {
+			return fmt.Errorf("%w:[%w]", ErrCredentials, err)
+		}
+	}
+	
+	if err := sdk.reauthenticate(ctx, apiKey); err != nil {
+		return err
+	}
+	
+	sdk.credentialsMu.Lock()
+	if setter, ok := sdk.currentClient().(apiKeySetter); ok {
+		setter.SetAPIKey(apiKey)
+	}
+	if static || credentials == nil {
+		sdk.credentials = StaticCredentials(apiKey)
+	} else {
+		sdk.rotatedKey, sdk.rotatedFrom = apiKey, providerKey
+	}
+	sdk.rotations++
+	sdk.credentialsMu.Unlock()
+	
+	log.Println("API key rotated", sdk.session())
+	return nil
+}
+
+// reauthenticate sends the key with the current session, clients keeping the key are updated by the caller
+func (sdk *SDK) reauthenticate(ctx context.Context, apiKey string) error {
+	if auth, ok := sdk.currentClient().(sessionAuthenticator); ok {
+		return auth.Reauthenticate(ctx, apiKey)
+	}
+	
+	_, err := sdk.Connect(ctx, NewConnectReq{
+		APIKey:                apiKey,
+		ConnectionSessionUUID: sdk.session(),
+	})
+	return err
+}
+
+// credentialsAPIKey returns the key of the credential provider, or the client key without one.
+// The client must be created
+func (sdk *SDK) credentialsAPIKey(ctx context.Context) (string, error) {
+	sdk.credentialsMu.Lock()
+	credentials := sdk.credentials
+	sdk.credentialsMu.Unlock()
+	
+	if credentials == nil {
+		return sdk.currentClient().APIKey(), nil
+	}
+	return sdk.providerAPIKey(ctx, credentials)
+}
+
+// providerAPIKey returns the key of credentials, or the rotated key while the provider returns the key it replaced
+func (sdk *SDK) providerAPIKey(ctx context.Context, credentials CredentialProvider) (string, error) {
+	apiKey, err := credentials.APIKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w:[%w]", ErrCredentials, err)
+	}
+	
+	sdk.credentialsMu.Lock()
+	defer sdk.credentialsMu.Unlock()
+	
+	if sdk.rotatedKey != "" {
+		if apiKey == sdk.rotatedFrom {
+			return sdk.rotatedKey, nil
+		}
+		// The provider returns a newer key than the rotated one
+		sdk.rotatedKey, sdk.rotatedFrom = "", ""
+	}
+	return apiKey, nil
+}
+
+// watchCredentials rotates the key whenever the credential provider reports a change, until done is closed
+func (sdk *SDK) watchCredentials(watcher CredentialWatcher, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-watcher.Changed():
+		}
+		
+		ctx, cancel := context.WithTimeout(context.Background(), sdk.requestTimeout())
+		
+		apiKey, err := sdk.credentialsAPIKey(ctx)
+		
+		if err == nil && apiKey != sdk.currentClient().APIKey() {
+			err = sdk.RotateAPIKey(ctx, apiKey)
+		}
+		if err != nil {
+			log.Println("API key rotation failed:", err)
+		}
+		cancel()
+	}
+}
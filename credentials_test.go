@@ -0,0 +1,224 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialProviders(t *testing.T) {
+	ctx := context.Background()
+	
+	apiKey, err := StaticCredentials("static-key").APIKey(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "static-key", apiKey)
+	
+	_, err = StaticCredentials("").APIKey(ctx)
+	assert.ErrorIs(t, err, ErrApiKeyRequired)
+	
+	t.Setenv("RUNWARE_TEST_KEY", "env-key")
+	apiKey, err = EnvCredentials("RUNWARE_TEST_KEY").APIKey(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "env-key", apiKey)
+	
+	_, err = EnvCredentials("RUNWARE_TEST_MISSING_KEY").APIKey(ctx)
+	assert.ErrorIs(t, err, ErrApiKeyRequired)
+	
+	apiKey, err = CredentialFunc(func(context.Context) (string, error) {
+		return "func-key", nil
+	}).APIKey(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "func-key", apiKey)
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	assert.NoError(t, os.WriteFile(path, []byte("old-key\n"), 0o600))
+	
+	creds, err := NewFileCredentials(path, 10*time.Millisecond)
+	assert.NoError(t, err)
+	defer creds.Close()
+	
+	apiKey, err := creds.APIKey(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "old-key", apiKey)
+	
+	assert.NoError(t, os.WriteFile(path, []byte("new-key\n"), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	
+	select {
+	case <-creds.Changed():
+	case <-time.After(time.Second):
+		t.Fatal("change not notified")
+	}
+	apiKey, err = creds.APIKey(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "new-key", apiKey)
+	
+	_, err = NewFileCredentials(filepath.Join(t.TempDir(), "missing"), 0)
+	assert.ErrorIs(t, err, ErrCredentials)
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	server := newFakeServer().acceptSessions(0)
+	sdk, err := NewSDK(SDKConfig{
+		Client:      server.client(),
+		Credentials: StaticCredentials("old-key"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, NewConnectReq{APIKey: "old-key"}, server.lastConnect())
+	
+	assert.ErrorIs(t, sdk.RotateAPIKey(context.Background(), ""), ErrApiKeyRequired)
+	
+	assert.NoError(t, sdk.RotateAPIKey(context.Background(), "new-key"))
+	assert.Equal(t, NewConnectReq{APIKey: "new-key", ConnectionSessionUUID: "session-1"}, server.lastConnect())
+	
+	// Reconnections resume the session with the rotated key
+	events, unsubscribe := sdk.Subscribe(1)
+	defer unsubscribe()
+	
+	server.reconnected <- struct{}{}
+	<-events
+	assert.Equal(t, NewConnectReq{APIKey: "new-key", ConnectionSessionUUID: "session-1"}, server.lastConnect())
+}
+
+func TestRotateAPIKeyFromWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	assert.NoError(t, os.WriteFile(path, []byte("old-key"), 0o600))
+	
+	creds, err := NewFileCredentials(path, 10*time.Millisecond)
+	assert.NoError(t, err)
+	defer creds.Close()
+	
+	server := newFakeServer().acceptSessions(0)
+	_, err = NewSDK(SDKConfig{
+		Client:      server.client(),
+		Credentials: creds,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "old-key", server.lastConnect().APIKey)
+	
+	assert.NoError(t, os.WriteFile(path, []byte("new-key"), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	
+	assert.Eventually(t, func() bool {
+		return server.lastConnect() == NewConnectReq{APIKey: "new-key", ConnectionSessionUUID: "session-1"}
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPoolReauthenticate(t *testing.T) {
+	pool, session := newTestPool(t, 2)
+	
+	assert.NoError(t, pool.Reauthenticate(context.Background(), "new-key"))
+	assert.Equal(t, "new-key", pool.APIKey())
+	
+	for i := 0; i < 2; i++ {
+		s := session(i)
+		s.mu.Lock()
+		assert.Equal(t, []string{"key", "new-key"}, s.apiKeys)
+		s.mu.Unlock()
+	}
+}
+
+func TestRotateAPIKeyDoesNotBlockReconnections(t *testing.T) {
+	server := newFakeServer().acceptSessions(0)
+	answer := server.handlers[NewConnection]
+	server.handlers[NewConnection] = func(data json.RawMessage) error {
+		// The rotation round trip is never answered
+		if server.lastConnect().APIKey == "slow-key" {
+			return nil
+		}
+		return answer(data)
+	}
+	
+	sdk, err := NewSDK(SDKConfig{
+		Client:      server.client(),
+		Credentials: StaticCredentials("old-key"),
+	})
+	assert.NoError(t, err)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() {
+		_ = sdk.RotateAPIKey(ctx, "slow-key")
+	}()
+	assert.Eventually(t, func() bool {
+		return server.lastConnect().APIKey == "slow-key"
+	}, time.Second, time.Millisecond)
+	
+	events, unsubscribe := sdk.Subscribe(1)
+	defer unsubscribe()
+	
+	server.reconnected <- struct{}{}
+	select {
+	case ev := <-events:
+		assert.Equal(t, EventSessionResumed, ev.Type)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("session not resumed during the rotation")
+	}
+}
+
+func TestRotateAPIKeyWithDynamicProvider(t *testing.T) {
+	var providerKey atomic.Value
+	providerKey.Store("old-key")
+	
+	server := newFakeServer().acceptSessions(0)
+	sdk, err := NewSDK(SDKConfig{
+		Client: server.client(),
+		Credentials: CredentialFunc(func(ctx context.Context) (string, error) {
+			return providerKey.Load().(string), nil
+		}),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, sdk.RotateAPIKey(context.Background(), "new-key"))
+	
+	events, unsubscribe := sdk.Subscribe(1)
+	defer unsubscribe()
+	
+	// The provider still returns the replaced key, the rotated one is kept
+	server.reconnected <- struct{}{}
+	<-events
+	assert.Equal(t, NewConnectReq{APIKey: "new-key", ConnectionSessionUUID: "session-1"}, server.lastConnect())
+	
+	// Then the provider's newer key wins
+	providerKey.Store("newer-key")
+	server.reconnected <- struct{}{}
+	<-events
+	assert.Equal(t, NewConnectReq{APIKey: "newer-key", ConnectionSessionUUID: "session-1"}, server.lastConnect())
+}
+
+func TestRotateAPIKeyRejectedKeepsRequestsInFlight(t *testing.T) {
+	server := newFakeServer().acceptSessions(0).answerCaptions(100 * time.Millisecond)
+	answer := server.handlers[NewConnection]
+	server.handlers[NewConnection] = func(data json.RawMessage) error {
+		if server.lastConnect().APIKey == "bad-key" {
+			server.replyError(19, "Invalid API key", "")
+			return nil
+		}
+		return answer(data)
+	}
+	
+	sdk, err := NewSDK(SDKConfig{
+		Client:      server.client(),
+		Credentials: StaticCredentials("old-key"),
+	})
+	assert.NoError(t, err)
+	
+	captioned := make(chan error, 1)
+	go func() {
+		_, err := sdk.ImageToText(context.Background(), NewReverseImageClipReq{ImageUUID: "img-1"})
+		captioned <- err
+	}()
+	assert.Eventually(t, func() bool {
+		return len(receivedFake[NewReverseImageClipReq](server, NewReverseImageClip)) == 1
+	}, time.Second, time.Millisecond)
+	
+	assert.ErrorIs(t, sdk.RotateAPIKey(context.Background(), "bad-key"), ErrInvalidApiKey)
+	assert.NoError(t, <-captioned)
+}
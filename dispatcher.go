@@ -0,0 +1,218 @@
+package runware
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+const subscriptionBufferSize = 16
+
+// subscription receives the incoming frames of a single pending request
+type subscription struct {
+	event    string
+	taskUUID string
+	frames   chan []byte
+	done     chan struct{}
+}
+
+// dispatcher reads the client incoming messages and routes them to the pending requests
+// based on the response event and task UUID
+type dispatcher struct {
+	mu        sync.Mutex
+	subs      map[*subscription]struct{}
+	abandoned map[string]time.Time
+}
+
+func newDispatcher() *dispatcher {
+	return &dispatcher{
+		subs:      make(map[*subscription]struct{}),
+		abandoned: make(map[string]time.Time),
+	}
+}
+
+func (d *dispatcher) subscribe(event, taskUUID string) *subscription {
+	sub := &subscription{
+		event:    event,
+		taskUUID: taskUUID,
+		frames:   make(chan []byte, subscriptionBufferSize),
+		done:     make(chan struct{}),
+	}
+	
+	d.mu.Lock()
+	d.subs[sub] = struct{}{}
+	delete(d.abandoned, taskUUID)
+	d.mu.Unlock()
+	
+	return sub
+}
+
+func (d *dispatcher) unsubscribe(sub *subscription) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	if _, ok := d.subs[sub]; !ok {
+		return
+	}
+	delete(d.subs, sub)
+	close(sub.done)
+}
+
+// pending returns the number of requests waiting for a response
+func (d *dispatcher) pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	return len(d.subs)
+}
+
+// run dispatches the incoming messages until stop is closed
+func (d *dispatcher) run(incoming chan []byte, stop chan struct{}) {
+	for {
+		select {
+		case msg := <-incoming:
+			d.dispatch(msg)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (d *dispatcher) dispatch(msg []byte) {
+	var msgData map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &msgData); err != nil {
+		log.Println("Skipping message", ErrDecodeMessage, err)
+		return
+	}
+	
+	// Errors are delivered to the task they belong to, or to every pending request when unknown.
+	// Connection errors have no task, they only fail the pending connection
+	if isErrorFrame(msgData) {
+		var errData struct {
+			TaskUUID string `json:"taskUUID"`
+		}
+		_ = json.Unmarshal(msg, &errData)
+		if errData.TaskUUID == "" {
+			if subs := d.match(NewConnectionSessionUUID, "", false); len(subs) > 0 {
+				d.deliver(msg, subs)
+				return
+			}
+		}
+		d.deliver(msg, d.match("", errData.TaskUUID, true))
+		return
+	}
+	
+	for k, v := range msgData {
+		taskUUID := frameTaskUUID(v)
+		subs := d.match(k, taskUUID, false)
+		if len(subs) == 0 {
+			// Late frames of cancelled tasks and heartbeat pongs are expected
+			if k != Pong && !d.isAbandoned(taskUUID) {
+				log.Println("Skipping event", k, "no pending request")
+			}
+			continue
+		}
+		d.deliver(msg, subs)
+	}
+}
+
+func (d *dispatcher) match(event, taskUUID string, anyEvent bool) []*subscription {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	subs := make([]*subscription, 0)
+	for sub := range d.subs {
+		if !anyEvent && sub.event != event {
+			continue
+		}
+		if taskUUID != "" && sub.taskUUID != "" && sub.taskUUID != taskUUID {
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	
+	return subs
+}
+
+func (d *dispatcher) deliver(msg []byte, subs []*subscription) {
+	for _, sub := range subs {
+		select {
+		case sub.frames <- msg:
+		case <-sub.done:
+		}
+	}
+}
+
+func isErrorFrame(msgData map[string]json.RawMessage) bool {
+	var hasError bool
+	if v, ok := msgData["error"]; ok {
+		_ = json.Unmarshal(v, &hasError)
+	}
+	return hasError
+}
+
+// frameTaskUUID extracts the task UUID from an event payload. It is either set on the payload
+// itself or on the first element of its result lists (e.g. `images`, `texts`)
+func frameTaskUUID(v json.RawMessage) string {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(v, &payload); err != nil {
+		return ""
+	}
+	
+	if raw, ok := payload["taskUUID"]; ok {
+		var taskUUID string
+		_ = json.Unmarshal(raw, &taskUUID)
+		return taskUUID
+	}
+	
+	for _, raw := range payload {
+		var items []struct {
+			TaskUUID string `json:"taskUUID"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil || len(items) == 0 {
+			continue
+		}
+		if items[0].TaskUUID != "" {
+			return items[0].TaskUUID
+		}
+	}
+	
+	return ""
+}
+
+// dispatcher returns the SDK dispatcher and starts it on first use
+func (sdk *SDK) dispatcher() *dispatcher {
+	sdk.dispatchOnce.Do(func() {
+		sdk.dispatch = newDispatcher()
+	})
+	
+	// Lazy SDKs have no client to listen to before their first request, and a new one per connect attempt
+	if sdk.hasClient() {
+		client := sdk.currentClient()
+		sdk.listenMu.Lock()
+		// Closed SDKs don't listen anymore
+		if sdk.listening != client && !sdk.closed.Load() {
+			if sdk.listenStop != nil {
+				close(sdk.listenStop)
+			}
+			sdk.listening = client
+			sdk.listenStop = make(chan struct{})
+			go sdk.dispatch.run(client.Listen(), sdk.listenStop)
+		}
+		sdk.listenMu.Unlock()
+	}
+	return sdk.dispatch
+}
+
+// stopListening stops dispatching the messages of the current client
+func (sdk *SDK) stopListening() {
+	sdk.listenMu.Lock()
+	defer sdk.listenMu.Unlock()
+	
+	if sdk.listenStop != nil {
+		close(sdk.listenStop)
+	}
+	sdk.listening = nil
+	sdk.listenStop = nil
+}
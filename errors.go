@@ -5,15 +5,26 @@ import (
 )
 
 var (
-	ErrWsDial            = errors.New("cannot connect to ws")
-	ErrApiKeyRequired    = errors.New("api key is required")
-	ErrOutgoingIsNil     = errors.New("outgoing message cannot be nil")
-	ErrFieldRequired     = errors.New("field is required")
-	ErrFieldIncorrectVal = errors.New("field has incorrect value")
-	ErrWsUnknownError    = errors.New("unknown error")
-	ErrInvalidApiKey     = errors.New("invalid api key")
-	ErrRequestTimeout    = errors.New("request timeout")
-	ErrDecodeMessage     = errors.New("cannot decode message")
+	ErrWsDial              = errors.New("cannot connect to ws")
+	ErrWsReconnect         = errors.New("reconnection failed")
+	ErrApiKeyRequired      = errors.New("api key is required")
+	ErrOutgoingIsNil       = errors.New("outgoing message cannot be nil")
+	ErrFieldRequired       = errors.New("field is required")
+	ErrFieldIncorrectVal   = errors.New("field has incorrect value")
+	ErrWsUnknownError      = errors.New("unknown error")
+	ErrInvalidApiKey       = errors.New("invalid api key")
+	ErrRequestTimeout      = errors.New("request timeout")
+	ErrDecodeMessage       = errors.New("cannot decode message")
+	ErrModelIncompatible   = errors.New("model does not support requested settings")
+	ErrModelWithoutAIR     = errors.New("model has no AIR identifier")
+	ErrNoMoreResults       = errors.New("no more results available")
+	ErrRequestCancelled    = errors.New("request cancelled")
+	ErrNSFWContent         = errors.New("nsfw content")
+	ErrUnknownImageUUID    = errors.New("unknown image uuid")
+	ErrCredentials         = errors.New("cannot get credentials")
+	ErrTenantManagerClosed = errors.New("tenant manager closed")
+	ErrSDKClosed           = errors.New("sdk closed")
+	ErrNotSupported        = errors.New("not supported by the transport")
 )
 
 // Base64 Err validations
@@ -2,10 +2,8 @@ package runware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"time"
 	
 	"github.com/google/uuid"
 )
@@ -19,6 +17,8 @@ type NewControlNetsResp struct {
 	NNsfwContent  *bool  `json:"nNsfwContent"` // Pointer to handle null values
 	TaskUUID      string `json:"taskUUID"`
 	TimedOut      bool   `json:"timedOut"`
+	NSFWFlagged   int    `json:"nsfwFlagged"`
+	NSFWFiltered  int    `json:"nsfwFiltered"`
 }
 
 func (sdk *SDK) NewControlNets(ctx context.Context, req NewControlNetsReq) (*NewControlNetsResp, error) {
@@ -27,79 +27,24 @@ func (sdk *SDK) NewControlNets(ctx context.Context, req NewControlNetsReq) (*New
 		return nil, err
 	}
 	
-	sendReq := Request{
+	resp, err := Do(ctx, sdk, Operation[NewControlNetsReq, NewControlNetsResp]{
 		Event:         NewPreProcessControlNet,
 		ResponseEvent: NewPreProcessControlNet,
-		Data:          req,
+		TaskUUID:      req.TaskUUID,
+		Request:       req,
+	})
+	sdk.invalidateUploads(ctx, err, map[string][]string{"guideImageUUID": {req.GuideImageUUID}})
+	if resp != nil && errors.Is(err, ErrRequestTimeout) {
+		resp.TimedOut = true
 	}
 	
-	newControlNetsResp := &NewControlNetsResp{}
-	
-	responseChan := make(chan *NewControlNetsResp)
-	errChan := make(chan error)
-	
-	go func() {
-		defer close(responseChan)
-		defer close(errChan)
-		
-		for msg := range sdk.Client.Listen() {
-			var msgData map[string]interface{}
-			if err := json.Unmarshal(msg, &msgData); err != nil {
-				errChan <- fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
-				return
-			}
-			
-			// Check if is an error message first
-			if errMsg, ok := sdk.OnError(msgData); ok {
-				errChan <- errMsg
-				return
-			}
-			
-			for k, v := range msgData {
-				if k != sendReq.ResponseEvent {
-					log.Println("Skipping event", k, "Currently handling", sendReq.ResponseEvent)
-					continue
-				}
-				
-				bValue, err := interfaceToByte(v)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				err = json.Unmarshal(bValue, &newControlNetsResp)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				responseChan <- newControlNetsResp
-				return
-			}
+	if resp != nil {
+		if nsfwErr := resp.applyNSFWPolicy(sdk.resolveNSFWPolicy(req.NSFWPolicy)); nsfwErr != nil {
+			return nil, nsfwErr
 		}
-		
-	}()
-	
-	bSendReq, err := sendReq.ToEvent()
-	if err != nil {
-		return nil, err
 	}
 	
-	if err = sdk.Client.Send(bSendReq); err != nil {
-		return nil, err
-	}
-	
-	select {
-	case resp := <-responseChan:
-		return resp, nil
-	case err = <-errChan:
-		return nil, err
-	case <-time.After(timeoutSendResponse * time.Second):
-		newControlNetsResp.TimedOut = true
-		return newControlNetsResp, fmt.Errorf("%w:[%s]", ErrRequestTimeout, sendReq.Event)
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
+	return resp, err
 }
 
 func NewControlNetsReqDefaults() *NewControlNetsReq {
@@ -4,12 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"strings"
-	"time"
 	
 	"github.com/google/uuid"
 )
@@ -24,6 +23,8 @@ type NewImageUploadResp struct {
 	NewImageUUID string `json:"newImageUUID"` // Pointer to handle null values
 	TaskUUID     string `json:"taskUUID"`
 	TimedOut     bool   `json:"timedOut"`
+	// Cached is set when the image UUID comes from the upload cache
+	Cached bool `json:"-"`
 }
 
 func (sdk *SDK) ImageUpload(ctx context.Context, req NewImageUploadReq) (*NewImageUploadResp, error) {
@@ -32,80 +33,44 @@ func (sdk *SDK) ImageUpload(ctx context.Context, req NewImageUploadReq) (*NewIma
 		return nil, err
 	}
 	
-	sendReq := Request{
-		ID:            uuid.New().String(),
+	var hash string
+	if sdk.uploadCache != nil {
+		hash = imageContentHash(req.ImageBase64)
+		cached, ok, err := sdk.uploadCache.Get(ctx, hash)
+		if err != nil {
+			log.Println("Upload cache lookup failed", err)
+		}
+		if ok {
+			return &NewImageUploadResp{
+				NewImageSrc:  cached.ImageSrc,
+				NewImageUUID: cached.ImageUUID,
+				TaskUUID:     req.TaskUUID,
+				Cached:       true,
+			}, nil
+		}
+	}
+	
+	resp, err := Do(ctx, sdk, Operation[NewImageUploadReq, NewImageUploadResp]{
 		Event:         NewImageUpload,
 		ResponseEvent: NewUploadedImageUUID,
-		Data:          req,
+		TaskUUID:      req.TaskUUID,
+		Request:       req,
+	})
+	if resp != nil && errors.Is(err, ErrRequestTimeout) {
+		resp.TimedOut = true
 	}
 	
-	newImageUploadResp := &NewImageUploadResp{}
-	
-	responseChan := make(chan *NewImageUploadResp)
-	errChan := make(chan error)
-	
-	go func() {
-		defer close(responseChan)
-		defer close(errChan)
-		
-		for msg := range sdk.Client.Listen() {
-			var msgData map[string]interface{}
-			if err := json.Unmarshal(msg, &msgData); err != nil {
-				errChan <- fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
-				return
-			}
-			
-			// Check if is an error message first
-			if errMsg, ok := sdk.OnError(msgData); ok {
-				errChan <- errMsg
-				return
-			}
-			
-			for k, v := range msgData {
-				if k != sendReq.ResponseEvent {
-					log.Println("Skipping event", k, "Currently handling", sendReq.ResponseEvent)
-					continue
-				}
-				
-				bValue, err := interfaceToByte(v)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				err = json.Unmarshal(bValue, &newImageUploadResp)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				responseChan <- newImageUploadResp
-				return
-			}
+	if err == nil && hash != "" && resp.NewImageUUID != "" {
+		cacheErr := sdk.uploadCache.Set(ctx, hash, CachedUpload{
+			ImageUUID: resp.NewImageUUID,
+			ImageSrc:  resp.NewImageSrc,
+		})
+		if cacheErr != nil {
+			log.Println("Upload cache store failed", cacheErr)
 		}
-		
-	}()
-	
-	bSendReq, err := sendReq.ToEvent()
-	if err != nil {
-		return nil, err
-	}
-	
-	if err = sdk.Client.Send(bSendReq); err != nil {
-		return nil, err
 	}
 	
-	select {
-	case resp := <-responseChan:
-		return resp, nil
-	case err = <-errChan:
-		return nil, err
-	case <-time.After(timeoutSendResponse * time.Second):
-		newImageUploadResp.TimedOut = true
-		return newImageUploadResp, fmt.Errorf("%w:[%s]", ErrRequestTimeout, sendReq.Event)
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
+	return resp, err
 }
 
 func NewImageUploadReqDefaults() *NewImageUploadReq {
@@ -2,21 +2,37 @@ package runware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"strconv"
+	"math"
+	"math/rand/v2"
 	"time"
 	
 	"github.com/google/uuid"
 )
 
+const (
+	maxSteps         = 100
+	maxCFGScale      = 30.0
+	maxClipSkip      = 2
+	minOutputQuality = 20
+	maxOutputQuality = 99
+	
+	defaultModel = ModelAbsolutereality
+)
+
 type NewTaskReq Task
 
 type NewTaskResp struct {
 	Images                []Image `json:"images"`
 	TotalAvailableResults int     `json:"totalAvailableResults"`
 	TimedOut              bool    `json:"timedOut"`
+	NSFWFlagged           int     `json:"nsfwFlagged"`
+	NSFWFiltered          int     `json:"nsfwFiltered"`
+	// Manifest of the task, use Manifest.ForImage to reproduce a single image
+	Manifest *Manifest `json:"manifest,omitempty"`
+	// Pending is set on timed out tasks to wait for the remaining images
+	Pending *PendingImages `json:"-"`
 }
 
 func (sdk *SDK) NewImage(ctx context.Context, req NewTaskReq) (*NewTaskResp, error) {
@@ -24,99 +40,97 @@ func (sdk *SDK) NewImage(ctx context.Context, req NewTaskReq) (*NewTaskResp, err
 		return nil, err
 	}
 	
+	if req.PromptLanguageId == nil {
+		lang, err := sdk.detectPromptLanguage(ctx, req.PromptText)
+		if err != nil {
+			return nil, err
+		}
+		if lang != "" {
+			req.PromptLanguageId = &lang
+		}
+	}
+	
 	// In case `req.TaskType` is empty try to evaluate it
 	if req.TaskType == 0 {
 		req.TaskType = getTaskType(req.PromptText, req.ControlNet, "", req.ImageInitiatorUUID)
 	}
 	
 	req = *mergeNewTaskReqWithDefaults(&req)
+	if err := validateModelCompatibility(req); err != nil {
+		return nil, err
+	}
 	
-	newTaskReq := Request{
-		ID:            uuid.New().String(),
+	call, err := Start(ctx, sdk, Operation[NewTaskReq, NewTaskResp]{
 		Event:         NewTask,
 		ResponseEvent: NewImage,
-		Data:          req,
+		TaskUUID:      req.TaskUUID,
+		Request:       req,
+		Merge:         mergeNewTaskResp,
+	})
+	if err != nil {
+		return nil, err
 	}
 	
-	newTaskResp := &NewTaskResp{
-		Images: make([]Image, 0),
+	pending := &PendingImages{
+		req:        req,
+		call:       call,
+		nsfwPolicy: sdk.resolveNSFWPolicy(req.NSFWPolicy),
+	}
+	return pending.Wait(ctx, 0)
+}
+
+// PendingImages is a timed out NewImage task still collecting its images in the background
+type PendingImages struct {
+	req        NewTaskReq
+	call       *Call[NewTaskReq, NewTaskResp]
+	nsfwPolicy NSFWPolicy
+}
+
+// Wait waits for the remaining images. On timeout the images received so far are returned
+// along with ErrRequestTimeout and Wait can be called again. A zero timeout uses the SDK timeout
+func (p *PendingImages) Wait(ctx context.Context, timeout time.Duration) (*NewTaskResp, error) {
+	resp, err := p.call.Wait(ctx, timeout)
+	p.call.sdk.invalidateUploads(ctx, err, p.req.imageUUIDs())
+	if resp != nil && resp.Manifest == nil {
+		resp.Manifest = newManifest(p.req)
 	}
 	
-	responseChan := make(chan *NewTaskResp)
-	errChan := make(chan error)
-	
-	go func() {
-		defer close(responseChan)
-		defer close(errChan)
-		
-		currentCount := 0
-		for msg := range sdk.Client.Listen() {
-			var msgData map[string]interface{}
-			if err := json.Unmarshal(msg, &msgData); err != nil {
-				errChan <- fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
-				return
-			}
-			
-			// Check if is an error message first
-			if errMsg, ok := sdk.OnError(msgData); ok {
-				errChan <- errMsg
-				return
-			}
-			
-			for k, v := range msgData {
-				if k != newTaskReq.ResponseEvent {
-					log.Println("Skipping event", k, "Currently handling", newTaskReq.ResponseEvent)
-					continue
-				}
-				
-				bValue, err := interfaceToByte(v)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				var iterTaskResp *NewTaskResp
-				err = json.Unmarshal(bValue, &iterTaskResp)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				newTaskResp.Images = mergeImageResults(iterTaskResp.Images, newTaskResp.Images)
-				currentCount += len(iterTaskResp.Images)
-				fmt.Println("-> SET:", currentCount)
-				
-				if currentCount >= req.NumberResults {
-					responseChan <- newTaskResp
-					return
-				}
-				
-				time.Sleep(30 * time.Millisecond)
-			}
+	if resp != nil {
+		if nsfwErr := resp.applyNSFWPolicy(p.nsfwPolicy); nsfwErr != nil {
+			p.call.Cancel()
+			return nil, nsfwErr
 		}
-	}()
-	
-	bSendReq, err := newTaskReq.ToEvent()
-	if err != nil {
-		return nil, err
 	}
 	
-	if err = sdk.Client.Send(bSendReq); err != nil {
-		return nil, err
+	if resp != nil && errors.Is(err, ErrRequestTimeout) {
+		resp.TimedOut = true
+		resp.Pending = p
+		return resp, err
 	}
 	
-	select {
-	case resp := <-responseChan:
-		return resp, nil
-	case err = <-errChan:
-		return nil, err
-	case <-time.After(timeoutSendResponse * time.Second):
-		newTaskResp.TimedOut = true
-		return newTaskResp, fmt.Errorf("%w:[%s]", ErrRequestTimeout, newTaskReq.Event)
-	case <-ctx.Done():
-		return nil, ctx.Err()
+	p.call.Cancel()
+	return resp, err
+}
+
+// imageUUIDs returns the uploaded images the task refers to, by field
+func (req NewTaskReq) imageUUIDs() map[string][]string {
+	imageUUIDs := map[string][]string{
+		"imageInitiatorUUID": {req.ImageInitiatorUUID},
 	}
-	
+	for _, cn := range req.ControlNet {
+		imageUUIDs["guideImageUUID"] = append(imageUUIDs["guideImageUUID"], cn.GuideImageUUID)
+	}
+	return imageUUIDs
+}
+
+// Cancel stops waiting for the remaining images
+func (p *PendingImages) Cancel() {
+	p.call.Cancel()
+}
+
+// Done is closed once all images were received, or the task failed or was cancelled
+func (p *PendingImages) Done() <-chan struct{} {
+	return p.call.Done()
 }
 
 // NewTaskReqDefaults set requests defaults
@@ -128,19 +142,58 @@ func NewTaskReqDefaults() *NewTaskReq {
 		SizeId:        SizeSquare512,
 		Offset:        0,
 		NumberResults: 4,
-		ModelId:       strconv.Itoa(ModelAbsolutereality),
+		ModelId:       defaultModel.String(),
+		Seed:          randomSeed(),
+	}
+}
+
+// mergeNewTaskResp aggregates the images of an incoming frame until all requested results arrived
+func mergeNewTaskResp(req NewTaskReq, resp *NewTaskResp, frame *NewTaskResp) bool {
+	resp.Images = mergeImageResults(frame.Images, resp.Images)
+	
+	// Images the server reported no seed for get their effective one, the server increments
+	// the request seed for every result of the task
+	if req.Seed != 0 {
+		for idx := range resp.Images {
+			if resp.Images[idx].Seed == 0 {
+				resp.Images[idx].Seed = req.Seed + int64(req.Offset+idx)
+			}
+		}
+	}
+	
+	resp.TotalAvailableResults = frame.TotalAvailableResults
+	
+	if resp.Manifest == nil {
+		resp.Manifest = newManifest(req)
 	}
+	resp.Manifest.Images = resp.Images
+	
+	// The last page of a task can hold fewer results than requested
+	expected := req.NumberResults
+	if resp.TotalAvailableResults > 0 {
+		expected = min(expected, resp.TotalAvailableResults-req.Offset)
+	}
+	
+	return len(resp.Images) >= expected
 }
 
 func mergeImageResults(src, dest []Image) []Image {
+	if dest == nil {
+		dest = make([]Image, 0, len(src))
+	}
+
+srcLoop:
 	for _, img := range src {
 		for idx, destImg := range dest {
 			if img.ImageUUID == destImg.ImageUUID {
 				dest[idx].ImageAltText = img.ImageAltText
 				dest[idx].BNSFWContent = img.BNSFWContent
 				dest[idx].ImageSrc = img.ImageSrc
+				if img.Seed != 0 {
+					dest[idx].Seed = img.Seed
+				}
 				
-				return dest
+				continue srcLoop
 			}
 		}
 		
@@ -151,7 +204,22 @@ func mergeImageResults(src, dest []Image) []Image {
 }
 
 func mergeNewTaskReqWithDefaults(req *NewTaskReq) *NewTaskReq {
+	customDimensions := hasCustomDimensions(*req)
+	
+	// The default model is resolved first so its defaults apply too, they take precedence over the generic ones
+	if req.ModelId == "" {
+		req.ModelId = defaultModel.String()
+	}
+	if model, ok := modelFromTaskModelId(req.ModelId); ok {
+		applyModelDefaults(req, model)
+	}
+	
 	_ = MergeEventRequestsWithDefaults[*NewTaskReq](req, NewTaskReqDefaults())
+	
+	// Width and height replace the size preset
+	if customDimensions {
+		req.SizeId = 0
+	}
 	return req
 }
 
@@ -160,9 +228,65 @@ func validateNewTaskReq(req NewTaskReq) error {
 		return fmt.Errorf("%w:[%s]", ErrFieldRequired, "promptText")
 	}
 	
+	if req.PromptLanguageId != nil && !req.PromptLanguageId.Valid() {
+		return fmt.Errorf("%w:[%s]", ErrFieldIncorrectVal, "promptLanguageId")
+	}
+	
+	if err := validateDimensions(req); err != nil {
+		return err
+	}
+	
+	if err := validateGenerationParams(req); err != nil {
+		return err
+	}
+	
 	return nil
 }
 
+func validateGenerationParams(req NewTaskReq) error {
+	if req.Seed < 0 {
+		return fmt.Errorf("%w:[%s][>=0]", ErrFieldIncorrectVal, "seed")
+	}
+	
+	if req.Steps < 0 || req.Steps > maxSteps {
+		return fmt.Errorf("%w:[%s][0-%d]", ErrFieldIncorrectVal, "steps", maxSteps)
+	}
+	
+	if req.CFGScale < 0 || req.CFGScale > maxCFGScale {
+		return fmt.Errorf("%w:[%s][0-%v]", ErrFieldIncorrectVal, "CFGScale", maxCFGScale)
+	}
+	
+	if req.Scheduler != "" && !req.Scheduler.Valid() {
+		return fmt.Errorf("%w:[%s][%s]", ErrFieldIncorrectVal, "scheduler", req.Scheduler)
+	}
+	
+	if req.ClipSkip < 0 || req.ClipSkip > maxClipSkip {
+		return fmt.Errorf("%w:[%s][0-%d]", ErrFieldIncorrectVal, "clipSkip", maxClipSkip)
+	}
+	
+	if req.Strength < 0 || req.Strength > 1 {
+		return fmt.Errorf("%w:[%s][0-1]", ErrFieldIncorrectVal, "strength")
+	}
+	if req.Strength != 0 && req.ImageInitiatorUUID == "" {
+		return fmt.Errorf("%w:[%s]", ErrFieldRequired, "imageInitiatorUUID")
+	}
+	
+	if req.OutputFormat != "" && !req.OutputFormat.Valid() {
+		return fmt.Errorf("%w:[%s][%s]", ErrFieldIncorrectVal, "outputFormat", req.OutputFormat)
+	}
+	
+	if req.OutputQuality != 0 && (req.OutputQuality < minOutputQuality || req.OutputQuality > maxOutputQuality) {
+		return fmt.Errorf("%w:[%s][%d-%d]", ErrFieldIncorrectVal, "outputQuality", minOutputQuality, maxOutputQuality)
+	}
+	
+	return nil
+}
+
+// randomSeed picks the seed of tasks without one, so it can be reported back with the images
+func randomSeed() int64 {
+	return rand.Int64N(math.MaxInt32) + 1
+}
+
 func getTaskType(promptText string, controlNet []ControlNet, imageMaskUUID, imageInitiatorUUID string) int {
 	hasPrompt := promptText != ""
 	hasControlNet := len(controlNet) > 0
@@ -1,6 +1,7 @@
 package runware
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -95,3 +96,109 @@ func TestGetTaskType(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGenerationParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     NewTaskReq
+		wantErr error
+	}{
+		{
+			name: "Valid",
+			req: NewTaskReq{
+				Seed:          42,
+				Steps:         30,
+				CFGScale:      7.5,
+				Scheduler:     SchedulerEulerAncestral,
+				ClipSkip:      2,
+				OutputFormat:  OutputFormatWEBP,
+				OutputQuality: 90,
+			},
+		},
+		{
+			name:    "Steps out of range",
+			req:     NewTaskReq{Steps: 101},
+			wantErr: ErrFieldIncorrectVal,
+		},
+		{
+			name:    "Negative steps",
+			req:     NewTaskReq{Steps: -1},
+			wantErr: ErrFieldIncorrectVal,
+		},
+		{
+			name:    "Unset steps",
+			req:     NewTaskReq{Steps: 0},
+			wantErr: nil,
+		},
+		{
+			name:    "Unknown scheduler",
+			req:     NewTaskReq{Scheduler: "Unknown"},
+			wantErr: ErrFieldIncorrectVal,
+		},
+		{
+			name:    "Strength without initiator image",
+			req:     NewTaskReq{Strength: 0.5},
+			wantErr: ErrFieldRequired,
+		},
+		{
+			name:    "Strength with initiator image",
+			req:     NewTaskReq{Strength: 0.5, ImageInitiatorUUID: "image-uuid"},
+			wantErr: nil,
+		},
+		{
+			name:    "Output quality out of range",
+			req:     NewTaskReq{OutputQuality: 100},
+			wantErr: ErrFieldIncorrectVal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGenerationParams(tt.req)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("validateGenerationParams() unexpected error %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateGenerationParams() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeNewTaskReqUnsetParams(t *testing.T) {
+	req := mergeNewTaskReqWithDefaults(&NewTaskReq{PromptText: "A cat", ModelId: "civitai:1@1"})
+	
+	// The server picks the parameters the caller didn't set
+	if req.Steps != 0 || req.CFGScale != 0 || req.OutputFormat != "" || req.OutputQuality != 0 {
+		t.Errorf("mergeNewTaskReqWithDefaults() = steps %d, CFGScale %v, format %q, quality %d, want unset",
+			req.Steps, req.CFGScale, req.OutputFormat, req.OutputQuality)
+	}
+	if req.Seed == 0 {
+		t.Errorf("mergeNewTaskReqWithDefaults() seed unset, want random")
+	}
+}
+
+func TestMergeNewTaskRespSeed(t *testing.T) {
+	req := NewTaskReq{NumberResults: 2, Seed: 1234}
+	resp := &NewTaskResp{}
+	
+	done := mergeNewTaskResp(req, resp, &NewTaskResp{
+		Images: []Image{{ImageUUID: "img-1"}, {ImageUUID: "img-2", Seed: 1235}},
+	})
+	if !done {
+		t.Errorf("mergeNewTaskResp() = false, want true")
+	}
+	if resp.Images[0].Seed != 1234 || resp.Images[1].Seed != 1235 {
+		t.Errorf("mergeNewTaskResp() seeds = %d, %d, want 1234, 1235", resp.Images[0].Seed, resp.Images[1].Seed)
+	}
+	
+	// The results of later pages are offset
+	req.Offset = 2
+	resp = &NewTaskResp{}
+	mergeNewTaskResp(req, resp, &NewTaskResp{Images: []Image{{ImageUUID: "img-3"}, {ImageUUID: "img-4"}}})
+	if resp.Images[0].Seed != 1236 || resp.Images[1].Seed != 1237 {
+		t.Errorf("mergeNewTaskResp() seeds = %d, %d, want 1236, 1237", resp.Images[0].Seed, resp.Images[1].Seed)
+	}
+}
@@ -2,20 +2,24 @@ package runware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"time"
+	"sync"
 	
 	"github.com/google/uuid"
 )
 
+const (
+	maxPromptLength   = 380
+	maxPromptVersions = 5
+)
+
 type NewPromptEnhanceReq struct {
-	TaskUUID         string `json:"taskUUID"`
-	PromptText       string `json:"prompt"` // PromptText json should be `promptText`
-	PromptMaxLength  int    `json:"promptMaxLength"`
-	PromptVersions   int    `json:"promptVersions"`
-	PromptLanguageId int    `json:"promptLanguageId"`
+	TaskUUID         string    `json:"taskUUID"`
+	PromptText       string    `json:"promptText"`
+	PromptMaxLength  int       `json:"promptMaxLength"`
+	PromptVersions   int       `json:"promptVersions"`
+	PromptLanguageId *Language `json:"promptLanguageId,omitempty"`
 }
 
 type NewPromptEnhanceRes struct {
@@ -29,87 +33,70 @@ func (sdk *SDK) PromptEnhancer(ctx context.Context, req NewPromptEnhanceReq) (*N
 		return nil, err
 	}
 	
-	sendReq := Request{
-		ID:            uuid.New().String(),
+	if req.PromptLanguageId == nil {
+		lang, err := sdk.detectPromptLanguage(ctx, req.PromptText)
+		if err != nil {
+			return nil, err
+		}
+		if lang != "" {
+			req.PromptLanguageId = &lang
+		}
+	}
+	
+	resp, err := Do(ctx, sdk, Operation[NewPromptEnhanceReq, NewPromptEnhanceRes]{
 		Event:         NewPromptEnhance,
 		ResponseEvent: NewPromptEnhancer,
-		Data:          req,
+		TaskUUID:      req.TaskUUID,
+		Request:       req,
+	})
+	if resp != nil && errors.Is(err, ErrRequestTimeout) {
+		resp.TimedOut = true
 	}
 	
-	newPromptEnhanceRes := &NewPromptEnhanceRes{}
-	
-	responseChan := make(chan *NewPromptEnhanceRes)
-	errChan := make(chan error)
-	
-	go func() {
-		defer close(responseChan)
-		defer close(errChan)
-		
-		for msg := range sdk.Client.Listen() {
-			var msgData map[string]interface{}
-			if err := json.Unmarshal(msg, &msgData); err != nil {
-				errChan <- fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
-				return
-			}
-			
-			// Check if is an error message first
-			if errMsg, ok := sdk.OnError(msgData); ok {
-				errChan <- errMsg
-				return
-			}
-			
-			for k, v := range msgData {
-				if k != sendReq.ResponseEvent {
-					log.Println("Skipping event", k, "Currently handling", sendReq.ResponseEvent)
-					continue
-				}
-				
-				bValue, err := interfaceToByte(v)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				err = json.Unmarshal(bValue, &newPromptEnhanceRes)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				responseChan <- newPromptEnhanceRes
-				return
-			}
-			
-		}
-	}()
-	
-	bSendReq, err := sendReq.ToEvent()
+	return resp, err
+}
+
+// EnhancedImages images generated from one enhanced prompt version
+type EnhancedImages struct {
+	Prompt string
+	Resp   *NewTaskResp
+	Err    error
+}
+
+// EnhanceAndGenerate enhances a prompt into PromptVersions variants and runs one NewImage task per variant,
+// based on taskReq. Results are returned in the order of the enhanced prompts, with their own error
+func (sdk *SDK) EnhanceAndGenerate(ctx context.Context, enhanceReq NewPromptEnhanceReq, taskReq NewTaskReq) ([]EnhancedImages, error) {
+	enhanced, err := sdk.PromptEnhancer(ctx, enhanceReq)
 	if err != nil {
 		return nil, err
 	}
 	
-	if err = sdk.Client.Send(bSendReq); err != nil {
-		return nil, err
-	}
+	results := make([]EnhancedImages, len(enhanced.Texts))
 	
-	select {
-	case resp := <-responseChan:
-		return resp, nil
-	case err = <-errChan:
-		return nil, err
-	case <-time.After(timeoutSendResponse * time.Second):
-		newPromptEnhanceRes.TimedOut = true
-		return newPromptEnhanceRes, fmt.Errorf("%w:[%s]", ErrRequestTimeout, sendReq.Event)
-	case <-ctx.Done():
-		return nil, ctx.Err()
+	var wg sync.WaitGroup
+	for idx, text := range enhanced.Texts {
+		results[idx].Prompt = text.Text
+		
+		req := taskReq
+		req.TaskUUID = uuid.New().String()
+		req.PromptText = text.Text
+		
+		wg.Add(1)
+		go func(idx int, req NewTaskReq) {
+			defer wg.Done()
+			results[idx].Resp, results[idx].Err = sdk.NewImage(ctx, req)
+		}(idx, req)
 	}
+	wg.Wait()
+	
+	return results, nil
 }
 
 func NewPromptEnhanceReqDefaults() *NewPromptEnhanceReq {
 	return &NewPromptEnhanceReq{
-		TaskUUID:         uuid.New().String(),
-		PromptLanguageId: 1,
-		PromptVersions:   3,
+		TaskUUID:        uuid.New().String(),
+		PromptMaxLength: maxPromptLength,
+		PromptVersions:  3,
 	}
 }
 
@@ -119,12 +106,20 @@ func mergeNewPromptEnhanceReqDefaults(req *NewPromptEnhanceReq) *NewPromptEnhanc
 }
 
 func validateNewPromptEnhanceReq(req NewPromptEnhanceReq) error {
-	if req.PromptMaxLength < 1 || req.PromptVersions > 380 {
-		return fmt.Errorf("%w:[%s][1-380]", ErrFieldIncorrectVal, "promptMaxLength")
+	if req.PromptText == "" {
+		return fmt.Errorf("%w:[%s]", ErrFieldRequired, "promptText")
+	}
+	
+	if req.PromptLanguageId != nil && !req.PromptLanguageId.Valid() {
+		return fmt.Errorf("%w:[%s]", ErrFieldIncorrectVal, "promptLanguageId")
+	}
+	
+	if req.PromptMaxLength < 1 || req.PromptMaxLength > maxPromptLength {
+		return fmt.Errorf("%w:[%s][1-%d]", ErrFieldIncorrectVal, "promptMaxLength", maxPromptLength)
 	}
 	
-	if req.PromptVersions < 1 || req.PromptVersions > 5 {
-		return fmt.Errorf("%w:[%s][1-380]", ErrFieldIncorrectVal, "promptVersions")
+	if req.PromptVersions < 1 || req.PromptVersions > maxPromptVersions {
+		return fmt.Errorf("%w:[%s][1-%d]", ErrFieldIncorrectVal, "promptVersions", maxPromptVersions)
 	}
 	
 	return nil
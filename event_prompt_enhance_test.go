@@ -0,0 +1,99 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNewPromptEnhanceReq(t *testing.T) {
+	valid := *mergeNewPromptEnhanceReqDefaults(&NewPromptEnhanceReq{PromptText: "A cat"})
+	assert.NoError(t, validateNewPromptEnhanceReq(valid))
+	assert.Equal(t, maxPromptLength, valid.PromptMaxLength)
+	
+	tests := []struct {
+		name   string
+		modify func(req *NewPromptEnhanceReq)
+		err    error
+	}{
+		{name: "empty prompt", modify: func(req *NewPromptEnhanceReq) { req.PromptText = "" }, err: ErrFieldRequired},
+		{name: "max length too long", modify: func(req *NewPromptEnhanceReq) { req.PromptMaxLength = 381 }, err: ErrFieldIncorrectVal},
+		{name: "negative max length", modify: func(req *NewPromptEnhanceReq) { req.PromptMaxLength = -1 }, err: ErrFieldIncorrectVal},
+		{name: "too many versions", modify: func(req *NewPromptEnhanceReq) { req.PromptVersions = 6 }, err: ErrFieldIncorrectVal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			assert.ErrorIs(t, validateNewPromptEnhanceReq(req), tt.err)
+		})
+	}
+}
+
+func TestEnhanceAndGenerate(t *testing.T) {
+	incoming := make(chan []byte, 10)
+	var (
+		mu      sync.Mutex
+		prompts []string
+	)
+	
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				var msg map[string]json.RawMessage
+				if err := json.Unmarshal(b, &msg); err != nil {
+					return err
+				}
+				
+				if data, ok := msg[NewPromptEnhance]; ok {
+					var req NewPromptEnhanceReq
+					_ = json.Unmarshal(data, &req)
+					texts := make([]Text, req.PromptVersions)
+					for i := range texts {
+						texts[i] = Text{TaskUUID: req.TaskUUID, Text: fmt.Sprintf("%s v%d", req.PromptText, i+1)}
+					}
+					resp, _ := json.Marshal(map[string]NewPromptEnhanceRes{NewPromptEnhancer: {Texts: texts}})
+					incoming <- resp
+					return nil
+				}
+				
+				var req NewTaskReq
+				_ = json.Unmarshal(msg[NewTask], &req)
+				mu.Lock()
+				prompts = append(prompts, req.PromptText)
+				mu.Unlock()
+				incoming <- []byte(fmt.Sprintf(`{"newImages":{"images":[{"imageUUID":"img-%s","taskUUID":"%s"}]}}`, req.TaskUUID, req.TaskUUID))
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+	}
+	
+	results, err := sdk.EnhanceAndGenerate(context.Background(), NewPromptEnhanceReq{
+		PromptText:     "A cat",
+		PromptVersions: 2,
+	}, NewTaskReq{
+		NumberResults: 1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for i, res := range results {
+		assert.Equal(t, fmt.Sprintf("A cat v%d", i+1), res.Prompt)
+		assert.NoError(t, res.Err)
+		assert.Len(t, res.Resp.Images, 1)
+		assert.Equal(t, res.Prompt, res.Resp.Manifest.Request.PromptText)
+	}
+	assert.ElementsMatch(t, []string{"A cat v1", "A cat v2"}, prompts)
+	
+	_, err = sdk.EnhanceAndGenerate(context.Background(), NewPromptEnhanceReq{
+		PromptText:     "A cat",
+		PromptVersions: 6,
+	}, NewTaskReq{})
+	assert.ErrorIs(t, err, ErrFieldIncorrectVal)
+}
@@ -0,0 +1,71 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RawOptions optional settings for SendRaw
+type RawOptions struct {
+	// TaskUUID correlates the response frames. When empty it's read from the payload `taskUUID` field
+	TaskUUID string
+	// Timeout defaults to the SDK request timeout
+	Timeout time.Duration
+	// Into is a pointer the response is unmarshalled into. When nil the response is decoded as generic JSON
+	Into interface{}
+}
+
+// SendRaw sends an arbitrary event and waits for responseEvent. It's an escape hatch for server
+// features that are not wrapped by the SDK yet, going through the same middlewares, error handling and timeouts.
+// Payload can be any JSON marshallable value, or already encoded JSON as []byte or string
+func (sdk *SDK) SendRaw(ctx context.Context, event string, payload interface{}, responseEvent string, opts *RawOptions) (interface{}, error) {
+	if event == "" {
+		return nil, fmt.Errorf("%w:[%s]", ErrFieldRequired, "event")
+	}
+	if responseEvent == "" {
+		return nil, fmt.Errorf("%w:[%s]", ErrFieldRequired, "responseEvent")
+	}
+	if opts == nil {
+		opts = &RawOptions{}
+	}
+	
+	bPayload, err := interfaceToByte(payload)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(bPayload) {
+		return nil, fmt.Errorf("%w:[%s]", ErrFieldIncorrectVal, "payload")
+	}
+	
+	taskUUID := opts.TaskUUID
+	if taskUUID == "" {
+		taskUUID = frameTaskUUID(bPayload)
+	}
+	
+	resp, err := Do(ctx, sdk, Operation[json.RawMessage, json.RawMessage]{
+		Event:         event,
+		ResponseEvent: responseEvent,
+		TaskUUID:      taskUUID,
+		Request:       bPayload,
+		Timeout:       opts.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	if opts.Into != nil {
+		if err = json.Unmarshal(*resp, opts.Into); err != nil {
+			return nil, fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		return opts.Into, nil
+	}
+	
+	var decoded interface{}
+	if err = json.Unmarshal(*resp, &decoded); err != nil {
+		return nil, fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	
+	return decoded, nil
+}
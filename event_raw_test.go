@@ -0,0 +1,35 @@
+package runware
+
+import (
+	"context"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRaw(t *testing.T) {
+	sdk := newFakeServer().answerEcho(1).sdk()
+	
+	t.Run("Generic JSON", func(t *testing.T) {
+		resp, err := sdk.SendRaw(context.Background(), "echo", `{"taskUUID":"task-1","text":"hello"}`, "echoed", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"taskUUID": "task-1",
+			"texts":    []interface{}{"hello"},
+		}, resp)
+	})
+	
+	t.Run("Into type", func(t *testing.T) {
+		var into testEchoResp
+		_, err := sdk.SendRaw(context.Background(), "echo", testEchoReq{TaskUUID: "task-2", Text: "hello"}, "echoed", &RawOptions{
+			Into: &into,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, testEchoResp{TaskUUID: "task-2", Texts: []string{"hello"}}, into)
+	})
+	
+	t.Run("Invalid payload", func(t *testing.T) {
+		_, err := sdk.SendRaw(context.Background(), "echo", "not json", "echoed", nil)
+		assert.ErrorIs(t, err, ErrFieldIncorrectVal)
+	})
+}
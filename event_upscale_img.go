@@ -2,23 +2,24 @@ package runware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"time"
 	
 	"github.com/google/uuid"
 )
 
 type NewUpscaleGanReq struct {
-	TaskUUID      string `json:"taskUUID"`
-	ImageUUID     string `json:"imageUUID"`
-	UpscaleFactor int    `json:"upscaleFactor"`
+	TaskUUID      string     `json:"taskUUID"`
+	ImageUUID     string     `json:"imageUUID"`
+	UpscaleFactor int        `json:"upscaleFactor"`
+	NSFWPolicy    NSFWPolicy `json:"-"`
 }
 
 type NewUpscaleGanResp struct {
-	Images   []Image `json:"images"`
-	TimedOut bool    `json:"timedOut"`
+	Images       []Image `json:"images"`
+	TimedOut     bool    `json:"timedOut"`
+	NSFWFlagged  int     `json:"nsfwFlagged"`
+	NSFWFiltered int     `json:"nsfwFiltered"`
 }
 
 func (sdk *SDK) ImageUpscale(ctx context.Context, req NewUpscaleGanReq) (*NewUpscaleGanResp, error) {
@@ -27,79 +28,24 @@ func (sdk *SDK) ImageUpscale(ctx context.Context, req NewUpscaleGanReq) (*NewUps
 		return nil, err
 	}
 	
-	sendReq := Request{
-		ID:            uuid.New().String(),
+	resp, err := Do(ctx, sdk, Operation[NewUpscaleGanReq, NewUpscaleGanResp]{
 		Event:         NewUpscaleGan,
 		ResponseEvent: NewUpscaleGan,
-		Data:          req,
+		TaskUUID:      req.TaskUUID,
+		Request:       req,
+	})
+	sdk.invalidateUploads(ctx, err, map[string][]string{"imageUUID": {req.ImageUUID}})
+	if resp != nil && errors.Is(err, ErrRequestTimeout) {
+		resp.TimedOut = true
 	}
 	
-	newUpscaleGanResp := &NewUpscaleGanResp{}
-	
-	responseChan := make(chan *NewUpscaleGanResp)
-	errChan := make(chan error)
-	
-	go func() {
-		defer close(responseChan)
-		defer close(errChan)
-		
-		for msg := range sdk.Client.Listen() {
-			var msgData map[string]interface{}
-			if err := json.Unmarshal(msg, &msgData); err != nil {
-				errChan <- fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
-				return
-			}
-			
-			// Check if is an error message first
-			if errMsg, ok := sdk.OnError(msgData); ok {
-				errChan <- errMsg
-				return
-			}
-			
-			for k, v := range msgData {
-				if k != sendReq.ResponseEvent {
-					log.Println("Skipping event", k, "Currently handling", sendReq.ResponseEvent)
-					continue
-				}
-				
-				bValue, err := interfaceToByte(v)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				err = json.Unmarshal(bValue, &newUpscaleGanResp)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				
-				responseChan <- newUpscaleGanResp
-				return
-			}
+	if resp != nil {
+		if nsfwErr := resp.applyNSFWPolicy(sdk.resolveNSFWPolicy(req.NSFWPolicy)); nsfwErr != nil {
+			return nil, nsfwErr
 		}
-	}()
-	
-	bSendReq, err := sendReq.ToEvent()
-	if err != nil {
-		return nil, err
 	}
 	
-	if err = sdk.Client.Send(bSendReq); err != nil {
-		return nil, err
-	}
-	
-	select {
-	case resp := <-responseChan:
-		return resp, nil
-	case err = <-errChan:
-		return nil, err
-	case <-time.After(timeoutSendResponse * time.Second):
-		newUpscaleGanResp.TimedOut = true
-		return newUpscaleGanResp, fmt.Errorf("%w:[%s]", ErrRequestTimeout, sendReq.Event)
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
+	return resp, err
 }
 
 func NewUpscaleGanReqDefaults() *NewUpscaleGanReq {
@@ -17,6 +17,7 @@ const (
 	NewImageUpload           = "newImageUpload"
 	NewReverseImageClip      = "newReverseImageClip"
 	NewPromptEnhance         = "newPromptEnhance"
+	Ping                     = "ping"
 	Pong                     = "pong"
 )
 
@@ -0,0 +1,257 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+	
+	"github.com/google/uuid"
+)
+
+// pendingCallTTL maximum time a timed out call keeps collecting frames
+const pendingCallTTL = 10 * time.Minute
+
+// Operation describes a request/response pair executed by Do
+type Operation[Req any, Resp any] struct {
+	// Event of the outgoing request
+	Event string
+	// ResponseEvent the request is waiting for
+	ResponseEvent string
+	// TaskUUID correlates incoming frames with the request. When empty every ResponseEvent frame is accepted
+	TaskUUID string
+	Request  Req
+	// Timeout defaults to the SDK request timeout
+	Timeout time.Duration
+	// Merge aggregates an incoming frame into the response and reports whether the response is complete.
+	// When nil the first frame completes the request
+	Merge func(req Req, resp *Resp, frame *Resp) bool
+}
+
+// Do sends the operation request and waits for its response. Incoming frames are correlated
+// by response event and task UUID, decoded into Resp and aggregated with Merge.
+// On timeout the partial response is returned along with ErrRequestTimeout
+func Do[Req any, Resp any](ctx context.Context, sdk *SDK, op Operation[Req, Resp]) (*Resp, error) {
+	call, err := Start(ctx, sdk, op)
+	if err != nil {
+		return nil, err
+	}
+	defer call.Cancel()
+	
+	return call.Wait(ctx, op.Timeout)
+}
+
+// Call is an operation in flight. It collects the incoming frames in the background until
+// the response is complete, the call is cancelled or pendingCallTTL elapses
+type Call[Req any, Resp any] struct {
+	op      Operation[Req, Resp]
+	sdk     *SDK
+	ctx     context.Context
+	sendReq Request
+	sub     *subscription
+	
+	mu         sync.Mutex
+	resp       *Resp
+	err        error
+	done       chan struct{}
+	finishOnce sync.Once
+}
+
+// Start sends the operation request without waiting for the response, use Call.Wait to get it.
+// The call must be released with Call.Cancel if the response is not awaited until completion
+func Start[Req any, Resp any](ctx context.Context, sdk *SDK, op Operation[Req, Resp]) (*Call[Req, Resp], error) {
+	if sdk.closed.Load() {
+		return nil, ErrSDKClosed
+	}
+	// The connection request is sent while connecting
+	if op.Event != NewConnection {
+		if err := sdk.ensureConnected(ctx); err != nil {
+			return nil, err
+		}
+	}
+	
+	sendReq := Request{
+		ID:            uuid.New().String(),
+		Event:         op.Event,
+		ResponseEvent: op.ResponseEvent,
+		Data:          op.Request,
+	}
+	
+	if err := sdk.beforeSend(ctx, &sendReq); err != nil {
+		return nil, sdk.onRequestError(ctx, sendReq, err)
+	}
+	if req, ok := sendReq.Data.(Req); ok {
+		op.Request = req
+	}
+	
+	call := &Call[Req, Resp]{
+		op:      op,
+		sdk:     sdk,
+		ctx:     ctx,
+		sendReq: sendReq,
+		sub:     sdk.dispatcher().subscribe(sendReq.ResponseEvent, op.TaskUUID),
+		resp:    new(Resp),
+		done:    make(chan struct{}),
+	}
+	
+	sdk.registerTask(op.TaskUUID, call)
+	
+	if err := sdk.send(sendReq); err != nil {
+		call.finish(err)
+		return nil, sdk.onRequestError(ctx, sendReq, err)
+	}
+	
+	go call.collect()
+	
+	return call, nil
+}
+
+// Wait waits for the complete response. On timeout a copy of the partial response is returned
+// along with ErrRequestTimeout while the call keeps collecting frames, so Wait can be called again.
+// A zero timeout uses the operation or SDK timeout
+func (c *Call[Req, Resp]) Wait(ctx context.Context, timeout time.Duration) (*Resp, error) {
+	if timeout == 0 {
+		timeout = c.op.Timeout
+	}
+	if timeout == 0 {
+		timeout = c.sdk.requestTimeout()
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	
+	select {
+	case <-c.done:
+		c.mu.Lock()
+		err := c.err
+		c.mu.Unlock()
+		
+		if err != nil {
+			return nil, c.sdk.onRequestError(ctx, c.sendReq, err)
+		}
+		return c.Response(), nil
+	case <-timer.C:
+		return c.Response(), c.sdk.onRequestError(ctx, c.sendReq, fmt.Errorf("%w:[%s]", ErrRequestTimeout, c.sendReq.Event))
+	case <-ctx.Done():
+		// The caller gave up on the task
+		c.abort(ctx.Err())
+		return nil, c.sdk.onRequestError(ctx, c.sendReq, ctx.Err())
+	}
+}
+
+// Response returns a copy of the response collected so far
+func (c *Call[Req, Resp]) Response() *Resp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	// Responses are plain JSON payloads, a JSON round trip is a deep copy
+	resp := new(Resp)
+	if b, err := json.Marshal(c.resp); err == nil {
+		_ = json.Unmarshal(b, resp)
+	}
+	return resp
+}
+
+// Done is closed once the call completed, failed or was cancelled
+func (c *Call[Req, Resp]) Done() <-chan struct{} {
+	return c.done
+}
+
+// Cancel stops collecting frames and releases the call. When the response is not complete yet
+// the task is abandoned, see SDK.Cancel
+func (c *Call[Req, Resp]) Cancel() {
+	c.abort(ErrRequestCancelled)
+}
+
+func (c *Call[Req, Resp]) abort(err error) {
+	if c.finish(err) {
+		_ = c.sdk.abandonTask(context.WithoutCancel(c.ctx), c.op.TaskUUID)
+		// The abort message is a follow-up of the task
+		c.sdk.releaseTask(c.op.TaskUUID)
+	}
+}
+
+// finish releases the call, it reports whether the call was still running
+func (c *Call[Req, Resp]) finish(err error) bool {
+	finished := false
+	c.finishOnce.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+		
+		c.sdk.dispatcher().unsubscribe(c.sub)
+		c.sdk.unregisterTask(c.op.TaskUUID, c)
+		c.sdk.releaseTask(c.op.TaskUUID)
+		close(c.done)
+		finished = true
+	})
+	return finished
+}
+
+// collect merges the incoming frames into the response until it's complete
+func (c *Call[Req, Resp]) collect() {
+	ttl := time.NewTimer(pendingCallTTL)
+	defer ttl.Stop()
+	
+	for {
+		select {
+		case msg := <-c.sub.frames:
+			c.mu.Lock()
+			done, err := c.op.handleFrame(c.ctx, c.sdk, c.sendReq, msg, c.resp)
+			c.mu.Unlock()
+			
+			if err != nil || done {
+				c.finish(err)
+				return
+			}
+		case <-ttl.C:
+			c.abort(fmt.Errorf("%w:[%s]", ErrRequestTimeout, c.sendReq.Event))
+			return
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// handleFrame decodes an incoming frame and merges it into resp
+func (op Operation[Req, Resp]) handleFrame(ctx context.Context, sdk *SDK, sendReq Request, msg []byte, resp *Resp) (bool, error) {
+	msg, err := sdk.afterReceive(ctx, sendReq, msg)
+	if err != nil {
+		return false, err
+	}
+	if msg == nil {
+		return false, nil
+	}
+	
+	var msgData map[string]interface{}
+	if err = json.Unmarshal(msg, &msgData); err != nil {
+		return false, fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	
+	// Check if is an error message first
+	if errMsg, ok := sdk.OnError(msgData); ok {
+		return false, errMsg
+	}
+	
+	v, ok := msgData[sendReq.ResponseEvent]
+	if !ok {
+		return false, nil
+	}
+	
+	bValue, err := interfaceToByte(v)
+	if err != nil {
+		return false, err
+	}
+	
+	frame := new(Resp)
+	if err = json.Unmarshal(bValue, frame); err != nil {
+		return false, fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	
+	if op.Merge == nil {
+		*resp = *frame
+		return true, nil
+	}
+	
+	return op.Merge(op.Request, resp, frame), nil
+}
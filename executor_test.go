@@ -0,0 +1,135 @@
+package runware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+type testEchoReq struct {
+	TaskUUID string `json:"taskUUID"`
+	Text     string `json:"text"`
+}
+
+type testEchoResp struct {
+	TaskUUID string   `json:"taskUUID"`
+	Texts    []string `json:"texts"`
+}
+
+func echoOperation(taskUUID string) Operation[testEchoReq, testEchoResp] {
+	return Operation[testEchoReq, testEchoResp]{
+		Event:         "echo",
+		ResponseEvent: "echoed",
+		TaskUUID:      taskUUID,
+		Request: testEchoReq{
+			TaskUUID: taskUUID,
+			Text:     "text-" + taskUUID,
+		},
+		Merge: func(req testEchoReq, resp *testEchoResp, frame *testEchoResp) bool {
+			resp.TaskUUID = frame.TaskUUID
+			resp.Texts = append(resp.Texts, frame.Texts...)
+			return len(resp.Texts) >= 2
+		},
+	}
+}
+
+func TestDoCorrelatesConcurrentRequests(t *testing.T) {
+	sdk := newFakeServer().answerEcho(2).sdk()
+	
+	var wg sync.WaitGroup
+	for _, taskUUID := range []string{"task-1", "task-2", "task-3"} {
+		wg.Add(1)
+		go func(taskUUID string) {
+			defer wg.Done()
+			
+			resp, err := Do(context.Background(), sdk, echoOperation(taskUUID))
+			assert.NoError(t, err)
+			assert.Equal(t, taskUUID, resp.TaskUUID)
+			assert.Equal(t, []string{"text-" + taskUUID, "text-" + taskUUID}, resp.Texts)
+		}(taskUUID)
+	}
+	wg.Wait()
+	
+	assert.Equal(t, 0, sdk.dispatcher().pending())
+}
+
+func TestDoTimeoutReturnsPartialResponse(t *testing.T) {
+	sdk := newFakeServer().answerEcho(1).sdk()
+	
+	op := echoOperation("task-1")
+	op.Timeout = 50 * time.Millisecond
+	
+	resp, err := Do(context.Background(), sdk, op)
+	assert.ErrorIs(t, err, ErrRequestTimeout)
+	assert.Equal(t, []string{"text-task-1"}, resp.Texts)
+}
+
+func TestDoErrorFrame(t *testing.T) {
+	incoming := make(chan []byte, 1)
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				incoming <- []byte(`{"error":true,"errorId":19,"errorMessage":"Invalid API key"}`)
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+	}
+	
+	_, err := sdk.Connect(context.Background(), NewConnectReq{APIKey: "invalid"})
+	assert.ErrorIs(t, err, ErrInvalidApiKey)
+}
+
+func TestNewImagePendingAfterTimeout(t *testing.T) {
+	incoming := make(chan []byte, 2)
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				incoming <- []byte(`{"newImages":{"images":[{"imageUUID":"img-1","taskUUID":"task-1"}]}}`)
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+		timeout: 50 * time.Millisecond,
+	}
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+	})
+	assert.ErrorIs(t, err, ErrRequestTimeout)
+	assert.True(t, resp.TimedOut)
+	assert.Len(t, resp.Images, 1)
+	assert.NotNil(t, resp.Pending)
+	
+	incoming <- []byte(`{"newImages":{"images":[{"imageUUID":"img-2","taskUUID":"task-1"}]}}`)
+	
+	resp, err = resp.Pending.Wait(context.Background(), time.Second)
+	assert.NoError(t, err)
+	assert.False(t, resp.TimedOut)
+	assert.Nil(t, resp.Pending)
+	assert.Len(t, resp.Images, 2)
+	assert.Equal(t, 0, sdk.dispatcher().pending())
+}
+
+func TestCallCancel(t *testing.T) {
+	sdk := newFakeServer().answerEcho(1).sdk()
+	
+	call, err := Start(context.Background(), sdk, echoOperation("task-1"))
+	assert.NoError(t, err)
+	
+	call.Cancel()
+	<-call.Done()
+	
+	_, err = call.Wait(context.Background(), time.Second)
+	assert.ErrorIs(t, err, ErrRequestCancelled)
+	assert.Equal(t, 0, sdk.dispatcher().pending())
+}
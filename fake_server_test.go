@@ -0,0 +1,201 @@
+package runware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fakeServer answers the messages sent by the SDK with the handlers registered for their event
+type fakeServer struct {
+	incoming    chan []byte
+	reconnected chan struct{}
+	handlers    map[string]func(data json.RawMessage) error
+	closed      atomic.Bool
+	
+	// Replies scheduled with later and not sent yet
+	inFlight    int32
+	maxInFlight int32
+	
+	mu       sync.Mutex
+	received map[string][]json.RawMessage
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		incoming:    make(chan []byte, 64),
+		reconnected: make(chan struct{}),
+		handlers:    make(map[string]func(data json.RawMessage) error),
+		received:    make(map[string][]json.RawMessage),
+	}
+}
+
+// handleFake answers the messages of event with h, errors returned by h fail the send
+func handleFake[Req any](s *fakeServer, event string, h func(req Req) error) *fakeServer {
+	s.handlers[event] = func(data json.RawMessage) error {
+		var req Req
+		if err := json.Unmarshal(data, &req); err != nil {
+			return err
+		}
+		return h(req)
+	}
+	return s
+}
+
+// receivedFake returns the messages of event received by the server, in order
+func receivedFake[Req any](s *fakeServer, event string) []Req {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	reqs := make([]Req, 0, len(s.received[event]))
+	for _, data := range s.received[event] {
+		var req Req
+		_ = json.Unmarshal(data, &req)
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// reply sends the response frame of event to the SDK
+func (s *fakeServer) reply(event string, resp interface{}) {
+	b, _ := json.Marshal(map[string]interface{}{event: resp})
+	s.incoming <- b
+}
+
+// replyError sends an error frame to the SDK, it's routed by taskUUID when not empty
+func (s *fakeServer) replyError(errorID int, message, taskUUID string) {
+	b, _ := json.Marshal(map[string]interface{}{"error": true, "errorId": errorID, "errorMessage": message, "taskUUID": taskUUID})
+	s.incoming <- b
+}
+
+// later sends the response frame of event after delay
+func (s *fakeServer) later(delay time.Duration, event string, resp interface{}) {
+	if n := atomic.AddInt32(&s.inFlight, 1); n > atomic.LoadInt32(&s.maxInFlight) {
+		atomic.StoreInt32(&s.maxInFlight, n)
+	}
+	go func() {
+		time.Sleep(delay)
+		atomic.AddInt32(&s.inFlight, -1)
+		s.reply(event, resp)
+	}()
+}
+
+// acceptSessions answers connection requests with a session and pings with a pong, the first refused connections fail
+func (s *fakeServer) acceptSessions(refused int) *fakeServer {
+	handleFake(s, NewConnection, func(req NewConnectReq) error {
+		if len(receivedFake[NewConnectReq](s, NewConnection)) <= refused {
+			return errors.New("connection refused")
+		}
+		s.reply(NewConnectionSessionUUID, NewConnectResp{ConnectionSessionUUID: "session-1"})
+		return nil
+	})
+	return handleFake(s, Ping, func(req json.RawMessage) error {
+		s.reply(Pong, true)
+		return nil
+	})
+}
+
+// answerImages serves total results of every task, the nsfw ones are flagged
+func (s *fakeServer) answerImages(total int, nsfw ...int) *fakeServer {
+	return handleFake(s, NewTask, func(req NewTaskReq) error {
+		resp := NewTaskResp{TotalAvailableResults: total}
+		for i := req.Offset; i < min(req.Offset+req.NumberResults, total); i++ {
+			resp.Images = append(resp.Images, Image{
+				ImageUUID:    fmt.Sprintf("img-%d", i),
+				TaskUUID:     req.TaskUUID,
+				BNSFWContent: slices.Contains(nsfw, i),
+			})
+		}
+		s.reply(NewImage, resp)
+		return nil
+	})
+}
+
+// answerUploads stores every valid image under the UUID `img-<taskUUID>`
+func (s *fakeServer) answerUploads() *fakeServer {
+	return handleFake(s, NewImageUpload, func(req NewImageUploadReq) error {
+		if _, err := isValidBase64Image(req.ImageBase64); err != nil {
+			return err
+		}
+		s.reply(NewUploadedImageUUID, NewImageUploadResp{NewImageUUID: "img-" + req.TaskUUID, TaskUUID: req.TaskUUID})
+		return nil
+	})
+}
+
+// answerCaptions captions every image after delay
+func (s *fakeServer) answerCaptions(delay time.Duration) *fakeServer {
+	return handleFake(s, NewReverseImageClip, func(req NewReverseImageClipReq) error {
+		s.later(delay, NewReverseClip, NewReverseImageClipResp{
+			Texts: []Text{{TaskUUID: req.TaskUUID, Text: "caption of " + req.ImageUUID}},
+		})
+		return nil
+	})
+}
+
+// answerEcho answers every `echo` request with the given number of frames
+func (s *fakeServer) answerEcho(frames int) *fakeServer {
+	return handleFake(s, "echo", func(req testEchoReq) error {
+		for i := 0; i < frames; i++ {
+			s.reply("echoed", testEchoResp{TaskUUID: req.TaskUUID, Texts: []string{req.Text}})
+		}
+		return nil
+	})
+}
+
+// lastConnect returns the last connection request received
+func (s *fakeServer) lastConnect() NewConnectReq {
+	connects := receivedFake[NewConnectReq](s, NewConnection)
+	if len(connects) == 0 {
+		return NewConnectReq{}
+	}
+	return connects[len(connects)-1]
+}
+
+func (s *fakeServer) send(b []byte) error {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	
+	for event, data := range msg {
+		s.mu.Lock()
+		s.received[event] = append(s.received[event], data)
+		s.mu.Unlock()
+		
+		if h, ok := s.handlers[event]; ok {
+			if err := h(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// client returns a mocked client speaking to the server, its API key is the one of the last connection
+func (s *fakeServer) client() *MockRunware {
+	return &MockRunware{
+		APIKeyFunc: func() string {
+			return s.lastConnect().APIKey
+		},
+		CloseFunc: func() error {
+			s.closed.Store(true)
+			return nil
+		},
+		SendFunc: s.send,
+		ListenFunc: func() chan []byte {
+			return s.incoming
+		},
+		ReconnectedFunc: func() chan struct{} {
+			return s.reconnected
+		},
+	}
+}
+
+// sdk returns an SDK talking to the server without connecting
+func (s *fakeServer) sdk() *SDK {
+	return &SDK{Client: s.client()}
+}
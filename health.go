@@ -0,0 +1,168 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const defaultHealthTimeout = 5 * time.Second
+
+// Health state of the SDK connection. Latency and Age are in milliseconds in JSON
+type Health struct {
+	Healthy      bool          `json:"healthy"`
+	Connected    bool          `json:"connected"`
+	Latency      time.Duration `json:"-"`
+	SessionUUID  string        `json:"sessionUUID"`
+	ConnectedAt  time.Time     `json:"connectedAt,omitempty"`
+	Age          time.Duration `json:"-"`
+	Reconnects   int           `json:"reconnects"`
+	PendingTasks int           `json:"pendingTasks"`
+	Error        string        `json:"error,omitempty"`
+}
+
+type healthJSON struct {
+	*healthAlias
+	LatencyMs int64 `json:"latencyMs"`
+	AgeMs     int64 `json:"ageMs"`
+}
+
+type healthAlias Health
+
+func (h Health) MarshalJSON() ([]byte, error) {
+	return json.Marshal(healthJSON{
+		healthAlias: (*healthAlias)(&h),
+		LatencyMs:   h.Latency.Milliseconds(),
+		AgeMs:       h.Age.Milliseconds(),
+	})
+}
+
+func (h *Health) UnmarshalJSON(b []byte) error {
+	v := healthJSON{healthAlias: (*healthAlias)(h)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	
+	h.Latency = time.Duration(v.LatencyMs) * time.Millisecond
+	h.Age = time.Duration(v.AgeMs) * time.Millisecond
+	return nil
+}
+
+// statelessClient is implemented by clients without session, they can't be pinged and are healthy while open
+type statelessClient interface {
+	stateless()
+}
+
+// Health pings the server and reports the connection state. The returned error is the ping one,
+// the SDK is healthy when the ping round trip succeeds. Stateless clients are healthy while open
+func (sdk *SDK) Health(ctx context.Context) (*Health, error) {
+	// Lazy SDKs create their client first, it tells whether it can be pinged
+	if err := sdk.ensureConnected(ctx); err != nil {
+		health := sdk.connectionHealth()
+		health.Error = err.Error()
+		return health, err
+	}
+	health := sdk.connectionHealth()
+	
+	if _, ok := sdk.currentClient().(statelessClient); ok {
+		if !health.Connected {
+			err := fmt.Errorf("%w:[%s]", ErrWsDial, "client closed")
+			health.Error = err.Error()
+			return health, err
+		}
+		health.Healthy = true
+		return health, nil
+	}
+	
+	latency, err := sdk.Ping(ctx)
+	if err != nil {
+		health.Error = err.Error()
+		return health, err
+	}
+	
+	health.Latency = latency
+	health.Healthy = true
+	return health, nil
+}
+
+// connectionHealth reports the connection state without pinging
+func (sdk *SDK) connectionHealth() *Health {
+	connectedAt, reconnects := sdk.lifecycle().stats()
+	
+	health := &Health{
+		ConnectedAt:  connectedAt,
+		Reconnects:   reconnects,
+		PendingTasks: sdk.dispatcher().pending(),
+	}
+	if sdk.hasClient() {
+		health.Connected = sdk.currentClient().Connected()
+		health.SessionUUID = sdk.session()
+	}
+	if !connectedAt.IsZero() {
+		health.Age = time.Since(connectedAt)
+	}
+	return health
+}
+
+// Ping sends a ping to the server and returns the round trip latency. Pongs carry no task UUID,
+// pings are sent one at a time so a pong answers the ping waiting for it
+func (sdk *SDK) Ping(ctx context.Context) (time.Duration, error) {
+	sdk.pingMu.Lock()
+	defer sdk.pingMu.Unlock()
+	
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	
+	timeout := defaultHealthTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	
+	start := time.Now()
+	_, err := Do(ctx, sdk, Operation[bool, json.RawMessage]{
+		Event:         Ping,
+		ResponseEvent: Pong,
+		Request:       true,
+		Timeout:       timeout,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// HealthHandler serves the SDK Health as JSON, with 503 when unhealthy. It suits readiness probes,
+// liveness probes can skip the ping with `?ping=false`, the SDK is then alive until closed. Lazy and
+// reconnecting SDKs are alive, they connect on their next request
+func (sdk *SDK) HealthHandler(timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+	
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var health *Health
+		if r.URL.Query().Get("ping") == "false" {
+			health = sdk.connectionHealth()
+			health.Healthy = !sdk.closed.Load()
+			if !health.Healthy {
+				health.Error = ErrSDKClosed.Error()
+			}
+		} else {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			health, _ = sdk.Health(ctx)
+		}
+		
+		status := http.StatusOK
+		if !health.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(health)
+	})
+}
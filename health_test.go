@@ -0,0 +1,199 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRunwareWsServer returns a websocket server opening sessions and answering pings
+func newRunwareWsServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			
+			var msgData map[string]json.RawMessage
+			_ = json.Unmarshal(msg, &msgData)
+			switch {
+			case msgData[NewConnection] != nil:
+				err = conn.WriteMessage(websocket.TextMessage, []byte(`{"newConnectionSessionUUID":{"connectionSessionUUID":"session-1"}}`))
+			case msgData[Ping] != nil:
+				err = conn.WriteMessage(websocket.TextMessage, []byte(`{"pong":true}`))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHealthWebSocket(t *testing.T) {
+	server := newRunwareWsServer(t)
+	
+	sdk, err := NewSDK(SDKConfig{
+		APIKey:   "key",
+		ConnAddr: ConnAddr("ws" + strings.TrimPrefix(server.URL, "http")),
+	})
+	assert.NoError(t, err)
+	defer sdk.Client.Close()
+	
+	assert.True(t, sdk.Client.Connected())
+	
+	health, err := sdk.Health(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, health.Healthy)
+	assert.True(t, health.Connected)
+	assert.Equal(t, "session-1", health.SessionUUID)
+	assert.Greater(t, health.Latency, time.Duration(0))
+	assert.False(t, health.ConnectedAt.IsZero())
+	assert.Equal(t, 0, health.PendingTasks)
+	
+	assert.NoError(t, sdk.Client.Close())
+	assert.False(t, sdk.Client.Connected())
+}
+
+func TestHealthHandler(t *testing.T) {
+	var (
+		incoming = make(chan []byte, 1)
+		answer   = true
+	)
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				if answer {
+					incoming <- []byte(`{"pong":true}`)
+				}
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+			ConnectedFunc: func() bool {
+				return answer
+			},
+		},
+		sessionKey: "session-1",
+	}
+	handler := sdk.HealthHandler(50 * time.Millisecond)
+	
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	
+	var health Health
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.True(t, health.Healthy)
+	assert.Equal(t, "session-1", health.SessionUUID)
+	
+	// The server stops answering
+	answer = false
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.NotEmpty(t, health.Error)
+	
+	// Liveness doesn't depend on the connection, only closed SDKs are dead
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz?ping=false", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	
+	assert.NoError(t, sdk.Close())
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz?ping=false", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestHealthHandlerLivenessLazy(t *testing.T) {
+	sdk, err := NewSDK(SDKConfig{Client: newFakeServer().client(), LazyConnect: true})
+	assert.NoError(t, err)
+	
+	rec := httptest.NewRecorder()
+	sdk.HealthHandler(50*time.Millisecond).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez?ping=false", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	
+	var health Health
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.True(t, health.Healthy)
+	assert.False(t, health.Connected)
+}
+
+func TestPingSerialised(t *testing.T) {
+	var (
+		incoming = make(chan []byte, 4)
+		mu       sync.Mutex
+		inFlight int
+		maxPings int
+	)
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				mu.Lock()
+				inFlight++
+				maxPings = max(maxPings, inFlight)
+				mu.Unlock()
+				
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+					incoming <- []byte(`{"pong":true}`)
+				}()
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+	}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			latency, err := sdk.Ping(context.Background())
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, latency, 10*time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	
+	// A pong only answers the ping waiting for it
+	assert.Equal(t, 1, maxPings)
+}
+
+func TestHealthJSON(t *testing.T) {
+	b, err := json.Marshal(Health{Healthy: true, Latency: 1500 * time.Microsecond, Age: 2 * time.Second})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"latencyMs":1`)
+	assert.Contains(t, string(b), `"ageMs":2000`)
+	assert.Contains(t, string(b), `"healthy":true`)
+	
+	var health Health
+	assert.NoError(t, json.Unmarshal(b, &health))
+	assert.True(t, health.Healthy)
+	assert.Equal(t, time.Millisecond, health.Latency)
+	assert.Equal(t, 2*time.Second, health.Age)
+}
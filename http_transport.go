@@ -0,0 +1,558 @@
+package runware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Transport how the SDK talks to the API
+type Transport int
+
+const (
+	// TransportWebSocket keeps a persistent socket, the default
+	TransportWebSocket Transport = iota
+	// TransportHTTP sends every task as an HTTP request, for environments that can't hold sockets
+	TransportHTTP
+)
+
+const (
+	ProdHTTPEnv ConnAddr = "https://api.runware.ai/v1"
+	
+	defaultHTTPTimeout = 2 * time.Minute
+	
+	restInvalidApiKey = "invalidApiKey"
+)
+
+// REST task types of the SDK events supported over HTTP
+const (
+	restAuthentication            = "authentication"
+	restImageInference            = "imageInference"
+	restImageUpload               = "imageUpload"
+	restImageUpscale              = "imageUpscale"
+	restImageCaption              = "imageCaption"
+	restPromptEnhance             = "promptEnhance"
+	restImageControlNetPreProcess = "imageControlNetPreProcess"
+)
+
+// httpRunware Runware client posting each task to the REST API. Requests are an array of tasks,
+// authenticated by a leading authentication task. Responses list their results in `data` and their
+// failures in `errors`, they are delivered through Listen as the socket messages of the task
+// so the SDK methods work unchanged. The REST API has no session and no ping
+type httpRunware struct {
+	apiKey   string
+	addr     ConnAddr
+	client   *http.Client
+	header   http.Header
+	incoming chan []byte
+	
+	onLifecycle func(LifecycleEvent)
+	
+	mu     sync.Mutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type restAuthenticationTask struct {
+	TaskType string `json:"taskType"`
+	APIKey   string `json:"apiKey"`
+}
+
+type restLora struct {
+	Model  string  `json:"model"`
+	Weight float64 `json:"weight"`
+}
+
+type restImageInferenceTask struct {
+	TaskType       string       `json:"taskType"`
+	TaskUUID       string       `json:"taskUUID"`
+	PositivePrompt string       `json:"positivePrompt"`
+	NegativePrompt string       `json:"negativePrompt,omitempty"`
+	Model          string       `json:"model"`
+	Width          int          `json:"width"`
+	Height         int          `json:"height"`
+	NumberResults  int          `json:"numberResults"`
+	SeedImage      string       `json:"seedImage,omitempty"`
+	Strength       float64      `json:"strength,omitempty"`
+	Seed           int64        `json:"seed,omitempty"`
+	Steps          int          `json:"steps,omitempty"`
+	CFGScale       float64      `json:"CFGScale,omitempty"`
+	Scheduler      Scheduler    `json:"scheduler,omitempty"`
+	ClipSkip       int          `json:"clipSkip,omitempty"`
+	Lora           []restLora   `json:"lora,omitempty"`
+	OutputType     string       `json:"outputType"`
+	OutputFormat   OutputFormat `json:"outputFormat,omitempty"`
+	OutputQuality  int          `json:"outputQuality,omitempty"`
+	CheckNSFW      bool         `json:"checkNSFW"`
+}
+
+type restImageUploadTask struct {
+	TaskType string `json:"taskType"`
+	TaskUUID string `json:"taskUUID"`
+	Image    string `json:"image"`
+}
+
+type restImageUpscaleTask struct {
+	TaskType      string `json:"taskType"`
+	TaskUUID      string `json:"taskUUID"`
+	InputImage    string `json:"inputImage"`
+	UpscaleFactor int    `json:"upscaleFactor"`
+	OutputType    string `json:"outputType"`
+}
+
+type restImageCaptionTask struct {
+	TaskType   string `json:"taskType"`
+	TaskUUID   string `json:"taskUUID"`
+	InputImage string `json:"inputImage"`
+}
+
+type restPromptEnhanceTask struct {
+	TaskType        string `json:"taskType"`
+	TaskUUID        string `json:"taskUUID"`
+	Prompt          string `json:"prompt"`
+	PromptMaxLength int    `json:"promptMaxLength"`
+	PromptVersions  int    `json:"promptVersions"`
+}
+
+type restControlNetPreProcessTask struct {
+	TaskType           string `json:"taskType"`
+	TaskUUID           string `json:"taskUUID"`
+	InputImage         string `json:"inputImage"`
+	PreProcessorType   string `json:"preProcessorType"`
+	Width              int    `json:"width,omitempty"`
+	Height             int    `json:"height,omitempty"`
+	LowThresholdCanny  int    `json:"lowThresholdCanny,omitempty"`
+	HighThresholdCanny int    `json:"highThresholdCanny,omitempty"`
+	OutputType         string `json:"outputType"`
+}
+
+// restResult item of the `data` list of a REST response
+type restResult struct {
+	TaskType       string `json:"taskType"`
+	TaskUUID       string `json:"taskUUID"`
+	ImageUUID      string `json:"imageUUID"`
+	ImageURL       string `json:"imageURL"`
+	Seed           int64  `json:"seed"`
+	NSFWContent    bool   `json:"NSFWContent"`
+	Text           string `json:"text"`
+	GuideImageUUID string `json:"guideImageUUID"`
+	GuideImageURL  string `json:"guideImageURL"`
+	InputImageUUID string `json:"inputImageUUID"`
+}
+
+// restError item of the `errors` list of a REST response
+type restError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Parameter string `json:"parameter"`
+	TaskType  string `json:"taskType"`
+	TaskUUID  string `json:"taskUUID"`
+}
+
+type restResponse struct {
+	Data   []restResult `json:"data"`
+	Errors []restError  `json:"errors"`
+}
+
+// NewHTTP creates a client sending tasks to the REST API. A nil httpClient uses a client with a 2 minutes timeout,
+// the proxy and TLS config of the dial options. Their headers are sent with every request
+func NewHTTP(cfg RunwareConfig, httpClient *http.Client) (Runware, error) {
+	if cfg.APIKey == "" {
+		return nil, ErrApiKeyRequired
+	}
+	
+	if cfg.ConnAddr == "" {
+		cfg.ConnAddr = ProdHTTPEnv
+	}
+	if httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.Proxy != nil {
+			transport.Proxy = cfg.Proxy
+		}
+		if cfg.TLSConfig != nil {
+			transport.TLSClientConfig = cfg.TLSConfig
+		}
+		httpClient = &http.Client{
+			Timeout:   defaultHTTPTimeout,
+			Transport: transport,
+		}
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	return &httpRunware{
+		apiKey: cfg.APIKey,
+		addr:   cfg.ConnAddr,
+		client: httpClient,
+		header: cfg.Header,
+		
+		onLifecycle: cfg.OnLifecycle,
+		incoming:    make(chan []byte),
+		ctx:         ctx,
+		cancel:      cancel,
+	}, nil
+}
+
+func (r *httpRunware) APIKey() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.apiKey
+}
+
+// Reauthenticate sends the new key with the next requests, there is no session to restore
+func (r *httpRunware) Reauthenticate(_ context.Context, apiKey string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	r.apiKey = apiKey
+	return nil
+}
+
+// Connected HTTP is stateless, the client is connected until closed
+func (r *httpRunware) Connected() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return !r.closed
+}
+
+// Close aborts the running requests
+func (r *httpRunware) Close() error {
+	r.mu.Lock()
+	closed := r.closed
+	r.closed = true
+	r.mu.Unlock()
+	
+	if !closed {
+		r.cancel()
+		emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventClosed})
+	}
+	return nil
+}
+
+// Send posts the task of the message in the background, its response is delivered through Listen.
+// Messages without REST task, e.g. the session and ping ones, fail with ErrNotSupported
+func (r *httpRunware) Send(msg []byte) error {
+	if msg == nil {
+		return ErrOutgoingIsNil
+	}
+	if !r.Connected() {
+		return fmt.Errorf("%w:[%s]", ErrWsDial, "client closed")
+	}
+	
+	var frame map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &frame); err != nil {
+		return fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	if len(frame) != 1 {
+		return fmt.Errorf("%w:[%s]", ErrDecodeMessage, "one event per message")
+	}
+	
+	for event, payload := range frame {
+		task, taskUUID, err := restTask(event, payload)
+		if err != nil {
+			return err
+		}
+		go r.post(event, task, taskUUID)
+	}
+	return nil
+}
+
+func (r *httpRunware) Listen() chan []byte {
+	return r.incoming
+}
+
+// stateless HTTP has no session to ping, see statelessClient
+func (r *httpRunware) stateless() {}
+
+// Reconnected never fires, there is no connection to restore
+func (r *httpRunware) Reconnected() chan struct{} {
+	return nil
+}
+
+func (r *httpRunware) post(event string, task interface{}, taskUUID string) {
+	body, err := json.Marshal([]interface{}{
+		restAuthenticationTask{TaskType: restAuthentication, APIKey: r.APIKey()},
+		task,
+	})
+	if err != nil {
+		r.deliverError(restError{Message: err.Error()}, taskUUID)
+		return
+	}
+	
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodPost, r.addr.String(), bytes.NewReader(body))
+	if err != nil {
+		r.deliverError(restError{Message: err.Error()}, taskUUID)
+		return
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	
+	res, err := r.client.Do(req)
+	if err != nil {
+		r.deliverError(restError{Message: err.Error()}, taskUUID)
+		return
+	}
+	defer res.Body.Close()
+	
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		r.deliverError(restError{Message: err.Error()}, taskUUID)
+		return
+	}
+	
+	var resp restResponse
+	if err = json.Unmarshal(b, &resp); err != nil || (len(resp.Data) == 0 && len(resp.Errors) == 0) {
+		restErr := restError{Message: fmt.Sprintf("%s: %s", res.Status, bytes.TrimSpace(b))}
+		if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+			restErr.Code = restInvalidApiKey
+		}
+		r.deliverError(restErr, taskUUID)
+		return
+	}
+	
+	// A task fails as a whole, the first error is reported
+	if len(resp.Errors) > 0 {
+		r.deliverError(resp.Errors[0], taskUUID)
+		return
+	}
+	
+	frame, err := legacyFrame(event, taskUUID, resp.Data)
+	if err != nil {
+		r.deliverError(restError{Message: err.Error()}, taskUUID)
+		return
+	}
+	r.deliver(frame)
+}
+
+func (r *httpRunware) deliver(msg []byte) {
+	select {
+	case r.incoming <- msg:
+	case <-r.ctx.Done():
+	}
+}
+
+// deliverError delivers the REST error as an error frame of the task
+func (r *httpRunware) deliverError(restErr restError, taskUUID string) {
+	errorID := 0
+	if restErr.Code == restInvalidApiKey {
+		errorID = 19
+	}
+	if restErr.TaskUUID != "" {
+		taskUUID = restErr.TaskUUID
+	}
+	
+	frame, _ := json.Marshal(map[string]interface{}{
+		"error":        true,
+		"errorId":      errorID,
+		"errorCode":    restErr.Code,
+		"errorMessage": restErr.Message,
+		"parameter":    restErr.Parameter,
+		"taskUUID":     taskUUID,
+	})
+	log.Println("HTTP request failed", taskUUID, restErr.Code, restErr.Message)
+	r.deliver(frame)
+}
+
+// restTask returns the REST task of an SDK event with its task UUID
+func restTask(event string, payload json.RawMessage) (interface{}, string, error) {
+	switch event {
+	case NewTask:
+		var req NewTaskReq
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, "", fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		task, err := restImageInferenceOf(req)
+		return task, req.TaskUUID, err
+	case NewImageUpload:
+		var req NewImageUploadReq
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, "", fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		return restImageUploadTask{
+			TaskType: restImageUpload,
+			TaskUUID: req.TaskUUID,
+			Image:    req.ImageBase64,
+		}, req.TaskUUID, nil
+	case NewUpscaleGan:
+		var req NewUpscaleGanReq
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, "", fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		return restImageUpscaleTask{
+			TaskType:      restImageUpscale,
+			TaskUUID:      req.TaskUUID,
+			InputImage:    req.ImageUUID,
+			UpscaleFactor: req.UpscaleFactor,
+			OutputType:    "URL",
+		}, req.TaskUUID, nil
+	case NewReverseImageClip:
+		var req NewReverseImageClipReq
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, "", fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		return restImageCaptionTask{
+			TaskType:   restImageCaption,
+			TaskUUID:   req.TaskUUID,
+			InputImage: req.ImageUUID,
+		}, req.TaskUUID, nil
+	case NewPromptEnhance:
+		var req NewPromptEnhanceReq
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, "", fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		return restPromptEnhanceTask{
+			TaskType:        restPromptEnhance,
+			TaskUUID:        req.TaskUUID,
+			Prompt:          req.PromptText,
+			PromptMaxLength: req.PromptMaxLength,
+			PromptVersions:  req.PromptVersions,
+		}, req.TaskUUID, nil
+	case NewPreProcessControlNet:
+		var req NewControlNetsReq
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, "", fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+		}
+		return restControlNetPreProcessTask{
+			TaskType:           restImageControlNetPreProcess,
+			TaskUUID:           req.TaskUUID,
+			InputImage:         req.GuideImageUUID,
+			PreProcessorType:   req.PreProcessorType,
+			Width:              req.Width,
+			Height:             req.Height,
+			LowThresholdCanny:  req.LowThresholdCanny,
+			HighThresholdCanny: req.HighThresholdCanny,
+			OutputType:         "URL",
+		}, req.TaskUUID, nil
+	default:
+		// e.g. newConnection and ping, the REST API has no session and no ping
+		return nil, "", fmt.Errorf("%w:[%s]", ErrNotSupported, event)
+	}
+}
+
+// restImageInferenceOf returns the REST task of an image task. Size presets are sent as their dimensions and
+// catalog models as their AIR identifier, the result pages and the ControlNet preprocessor names have no REST equivalent
+func restImageInferenceOf(req NewTaskReq) (restImageInferenceTask, error) {
+	if req.Offset > 0 {
+		return restImageInferenceTask{}, fmt.Errorf("%w:[%s]", ErrNotSupported, "offset")
+	}
+	if len(req.ControlNet) > 0 {
+		return restImageInferenceTask{}, fmt.Errorf("%w:[%s]", ErrNotSupported, "controlNet")
+	}
+	
+	width, height := req.Width, req.Height
+	if width == 0 && height == 0 {
+		size, ok := SizeByID(req.SizeId)
+		if !ok {
+			return restImageInferenceTask{}, fmt.Errorf("%w:[%s][%d]", ErrFieldIncorrectVal, "sizeId", req.SizeId)
+		}
+		width, height = size.Width, size.Height
+	}
+	
+	model := req.ModelId
+	if catalogModel, ok := modelFromTaskModelId(req.ModelId); ok {
+		air, err := catalogModel.AIR()
+		if err != nil {
+			return restImageInferenceTask{}, err
+		}
+		model = air
+	}
+	
+	loras := make([]restLora, 0, len(req.Lora))
+	for _, lora := range req.Lora {
+		loras = append(loras, restLora{Model: lora.ModelID, Weight: lora.Weight})
+	}
+	
+	return restImageInferenceTask{
+		TaskType:       restImageInference,
+		TaskUUID:       req.TaskUUID,
+		PositivePrompt: req.PromptText,
+		NegativePrompt: req.NegativePrompt,
+		Model:          model,
+		Width:          width,
+		Height:         height,
+		NumberResults:  req.NumberResults,
+		SeedImage:      req.ImageInitiatorUUID,
+		Strength:       req.Strength,
+		Seed:           req.Seed,
+		Steps:          req.Steps,
+		CFGScale:       req.CFGScale,
+		Scheduler:      req.Scheduler,
+		ClipSkip:       req.ClipSkip,
+		Lora:           loras,
+		OutputType:     "URL",
+		OutputFormat:   req.OutputFormat,
+		OutputQuality:  req.OutputQuality,
+		CheckNSFW:      true,
+	}, nil
+}
+
+// legacyFrame returns the socket message of the REST results of a task, in the response event of the SDK event
+func legacyFrame(event, taskUUID string, results []restResult) ([]byte, error) {
+	var frame interface{}
+	
+	switch event {
+	case NewTask:
+		resp := NewTaskResp{TotalAvailableResults: len(results)}
+		for _, res := range results {
+			resp.Images = append(resp.Images, restImage(res, taskUUID))
+		}
+		frame = map[string]NewTaskResp{NewImage: resp}
+	case NewImageUpload:
+		res := results[0]
+		frame = map[string]NewImageUploadResp{NewUploadedImageUUID: {
+			NewImageSrc:  res.ImageURL,
+			NewImageUUID: res.ImageUUID,
+			TaskUUID:     taskUUID,
+		}}
+	case NewUpscaleGan:
+		var resp NewUpscaleGanResp
+		for _, res := range results {
+			resp.Images = append(resp.Images, restImage(res, taskUUID))
+		}
+		frame = map[string]NewUpscaleGanResp{NewUpscaleGan: resp}
+	case NewReverseImageClip:
+		frame = map[string]NewReverseImageClipResp{NewReverseClip: {Texts: restTexts(results, taskUUID)}}
+	case NewPromptEnhance:
+		frame = map[string]NewPromptEnhanceRes{NewPromptEnhancer: {Texts: restTexts(results, taskUUID)}}
+	case NewPreProcessControlNet:
+		res := results[0]
+		frame = map[string]NewControlNetsResp{NewPreProcessControlNet: {
+			NewImageSrc:   res.GuideImageURL,
+			NewImageUUID:  res.GuideImageUUID,
+			InitImageUUID: res.InputImageUUID,
+			TaskUUID:      taskUUID,
+		}}
+	default:
+		return nil, fmt.Errorf("%w:[%s]", ErrNotSupported, event)
+	}
+	
+	return json.Marshal(frame)
+}
+
+func restImage(res restResult, taskUUID string) Image {
+	return Image{
+		ImageSrc:     res.ImageURL,
+		ImageUUID:    res.ImageUUID,
+		BNSFWContent: res.NSFWContent,
+		TaskUUID:     taskUUID,
+		Seed:         res.Seed,
+	}
+}
+
+func restTexts(results []restResult, taskUUID string) []Text {
+	texts := make([]Text, 0, len(results))
+	for _, res := range results {
+		texts = append(texts, Text{TaskUUID: taskUUID, Text: res.Text})
+	}
+	return texts
+}
@@ -0,0 +1,174 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+const pngDataURI = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAIAAACQd1PeAAAADElEQVR4nGP4z8AAAAMBAQDJ/pLvAAAAAElFTkSuQmCC"
+
+// newRESTServer returns a server answering REST task arrays the way the API does, with `data` and `errors` lists
+func newRESTServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tasks []map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&tasks); err != nil || len(tasks) != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		
+		auth, task := tasks[0], tasks[1]
+		if auth["taskType"] != "authentication" || auth["apiKey"] != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"errors":[{"code":"invalidApiKey","message":"Invalid API key","parameter":"apiKey","taskType":"authentication"}]}`)
+			return
+		}
+		
+		taskUUID := task["taskUUID"]
+		var data []map[string]interface{}
+		switch task["taskType"] {
+		case "imageInference":
+			if task["positivePrompt"] == "fail" {
+				_, _ = fmt.Fprintf(w, `{"errors":[{"code":"invalidPositivePrompt","message":"Invalid prompt","parameter":"positivePrompt","taskType":"imageInference","taskUUID":%q}]}`, taskUUID)
+				return
+			}
+			if task["seedImage"] == "expired" {
+				_, _ = fmt.Fprintf(w, `{"errors":[{"code":"invalidSeedImage","message":"Image not found","parameter":"seedImage","taskType":"imageInference","taskUUID":%q}]}`, taskUUID)
+				return
+			}
+			// The size preset is sent as dimensions, the images as URLs
+			assert.Equal(t, float64(512), task["width"])
+			assert.Equal(t, float64(768), task["height"])
+			assert.Equal(t, "civitai:4384@128713", task["model"])
+			assert.Equal(t, "URL", task["outputType"])
+			for i := 0; i < int(task["numberResults"].(float64)); i++ {
+				data = append(data, map[string]interface{}{
+					"taskType":    "imageInference",
+					"taskUUID":    taskUUID,
+					"imageUUID":   fmt.Sprintf("img-%d", i),
+					"imageURL":    fmt.Sprintf("https://im.runware.ai/image/img-%d.jpg", i),
+					"seed":        42 + i,
+					"NSFWContent": false,
+				})
+			}
+		case "imageUpload":
+			assert.Equal(t, pngDataURI, task["image"])
+			data = append(data, map[string]interface{}{"taskType": "imageUpload", "taskUUID": taskUUID, "imageUUID": "uploaded"})
+		case "imageCaption":
+			assert.Equal(t, "uploaded", task["inputImage"])
+			data = append(data, map[string]interface{}{"taskType": "imageCaption", "taskUUID": taskUUID, "text": "a cat"})
+		case "promptEnhance":
+			assert.Equal(t, "a cat", task["prompt"])
+			for i := 0; i < int(task["promptVersions"].(float64)); i++ {
+				data = append(data, map[string]interface{}{"taskType": "promptEnhance", "taskUUID": taskUUID, "text": fmt.Sprintf("a fluffy cat %d", i)})
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPTransport(t *testing.T) {
+	server := newRESTServer(t)
+	ctx := context.Background()
+	
+	sdk, err := NewSDK(SDKConfig{
+		APIKey:    "key",
+		ConnAddr:  ConnAddr(server.URL),
+		Transport: TransportHTTP,
+	})
+	assert.NoError(t, err)
+	assert.True(t, sdk.Client.Connected())
+	assert.Empty(t, sdk.sessionKey)
+	
+	// The catalog model is sent as its AIR identifier
+	resp, err := sdk.NewImage(ctx, NewTaskReq{
+		PromptText:    "A cat",
+		ModelId:       ModelDreamshaper.String(),
+		SizeId:        SizePortrait2to3,
+		NumberResults: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Images, 2)
+	assert.Equal(t, "https://im.runware.ai/image/img-0.jpg", resp.Images[0].ImageSrc)
+	assert.Equal(t, int64(42), resp.Images[0].Seed)
+	
+	uploaded, err := sdk.ImageUpload(ctx, NewImageUploadReq{ImageBase64: pngDataURI})
+	assert.NoError(t, err)
+	assert.Equal(t, "uploaded", uploaded.NewImageUUID)
+	
+	caption, err := sdk.ImageToText(ctx, NewReverseImageClipReq{ImageUUID: "uploaded"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a cat", caption.Texts[0].Text)
+	
+	enhanced, err := sdk.PromptEnhancer(ctx, NewPromptEnhanceReq{PromptText: "a cat", PromptVersions: 2, PromptMaxLength: 100})
+	assert.NoError(t, err)
+	assert.Len(t, enhanced.Texts, 2)
+	
+	_, err = sdk.NewImage(ctx, NewTaskReq{PromptText: "fail", ModelId: "runware:100@1", NumberResults: 1})
+	assert.ErrorIs(t, err, ErrWsUnknownError)
+	
+	_, err = sdk.NewImage(ctx, NewTaskReq{PromptText: "A cat", ModelId: "runware:100@1", NumberResults: 1, ImageInitiatorUUID: "expired", TaskType: ImageToImage})
+	assert.ErrorIs(t, err, ErrUnknownImageUUID)
+	
+	// Catalog models without AIR identifier can't be sent
+	_, err = sdk.NewImage(ctx, NewTaskReq{PromptText: "A cat", ModelId: ModelCyberrealistic.String(), NumberResults: 1})
+	assert.ErrorIs(t, err, ErrModelWithoutAIR)
+	
+	// Result pages have no REST equivalent
+	_, err = sdk.FetchPage(ctx, NewTaskReq{TaskUUID: "task", PromptText: "A cat", NumberResults: 1}, 1)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	
+	unauthorized, err := NewSDK(SDKConfig{
+		APIKey:    "wrong",
+		ConnAddr:  ConnAddr(server.URL),
+		Transport: TransportHTTP,
+	})
+	assert.NoError(t, err)
+	_, err = unauthorized.NewImage(ctx, NewTaskReq{PromptText: "A cat", NumberResults: 1})
+	assert.ErrorIs(t, err, ErrInvalidApiKey)
+	
+	assert.NoError(t, sdk.Client.Close())
+	assert.False(t, sdk.Client.Connected())
+	_, err = sdk.NewImage(ctx, NewTaskReq{PromptText: "A cat", NumberResults: 1})
+	assert.Error(t, err)
+}
+
+func TestHTTPTransportHealth(t *testing.T) {
+	sdk, err := NewSDK(SDKConfig{
+		APIKey:    "key",
+		ConnAddr:  ConnAddr(newRESTServer(t).URL),
+		Transport: TransportHTTP,
+	})
+	assert.NoError(t, err)
+	
+	// The REST API has no ping, the open client is healthy
+	health, err := sdk.Health(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, health.Healthy)
+	assert.True(t, health.Connected)
+	
+	rec := httptest.NewRecorder()
+	sdk.HealthHandler(time.Second).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	
+	_, err = sdk.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.ErrorIs(t, sdk.Client.Send([]byte(`{"newConnection":{"apiKey":"key"}}`)), ErrNotSupported)
+	
+	assert.NoError(t, sdk.Client.Close())
+	health, err = sdk.Health(context.Background())
+	assert.Error(t, err)
+	assert.False(t, health.Healthy)
+	assert.NotEmpty(t, health.Error)
+}
@@ -0,0 +1,148 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Language prompt language, identified by its ISO 639-1 code. The task `promptLanguageId` is a string
+type Language string
+
+// Supported prompt languages
+const (
+	LanguageEnglish    Language = "en"
+	LanguageSpanish    Language = "es"
+	LanguageFrench     Language = "fr"
+	LanguageGerman     Language = "de"
+	LanguageItalian    Language = "it"
+	LanguagePortuguese Language = "pt"
+	LanguageRussian    Language = "ru"
+	LanguageChinese    Language = "zh"
+	LanguageJapanese   Language = "ja"
+	LanguageKorean     Language = "ko"
+	LanguageArabic     Language = "ar"
+	LanguageHindi      Language = "hi"
+)
+
+var languages = map[Language]bool{
+	LanguageEnglish:    true,
+	LanguageSpanish:    true,
+	LanguageFrench:     true,
+	LanguageGerman:     true,
+	LanguageItalian:    true,
+	LanguagePortuguese: true,
+	LanguageRussian:    true,
+	LanguageChinese:    true,
+	LanguageJapanese:   true,
+	LanguageKorean:     true,
+	LanguageArabic:     true,
+	LanguageHindi:      true,
+}
+
+// Code returns the ISO 639-1 code of the language
+func (l Language) Code() string {
+	return string(l)
+}
+
+func (l Language) Valid() bool {
+	return languages[l]
+}
+
+// UnmarshalJSON accepts language codes with a region, e.g. `pt-BR`
+func (l *Language) UnmarshalJSON(b []byte) error {
+	var code string
+	if err := json.Unmarshal(b, &code); err != nil {
+		return fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	if code == "" {
+		*l = ""
+		return nil
+	}
+	
+	lang, err := LanguageFromCode(code)
+	if err != nil {
+		return err
+	}
+	*l = lang
+	return nil
+}
+
+// LanguageFromCode returns the language of an ISO 639-1 code (e.g. `es`, `pt-BR`)
+func LanguageFromCode(code string) (Language, error) {
+	code, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	if l := Language(code); l.Valid() {
+		return l, nil
+	}
+	return "", fmt.Errorf("%w:[%s][%s]", ErrFieldIncorrectVal, "language", code)
+}
+
+// LanguageDetector detects the language of prompts without an explicit language
+type LanguageDetector interface {
+	DetectLanguage(ctx context.Context, text string) (Language, error)
+}
+
+// LanguageDetectorFunc adapts a function to LanguageDetector
+type LanguageDetectorFunc func(ctx context.Context, text string) (Language, error)
+
+func (f LanguageDetectorFunc) DetectLanguage(ctx context.Context, text string) (Language, error) {
+	return f(ctx, text)
+}
+
+// ScriptLanguageDetector detects languages by their writing system. Latin scripts can't be
+// told apart and are reported as English, plug a dedicated detector for those
+type ScriptLanguageDetector struct{}
+
+func (ScriptLanguageDetector) DetectLanguage(_ context.Context, text string) (Language, error) {
+	counts := make(map[Language]int)
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts[LanguageJapanese]++
+		case unicode.Is(unicode.Han, r):
+			counts[LanguageChinese]++
+		case unicode.Is(unicode.Hangul, r):
+			counts[LanguageKorean]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts[LanguageRussian]++
+		case unicode.Is(unicode.Arabic, r):
+			counts[LanguageArabic]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts[LanguageHindi]++
+		case unicode.Is(unicode.Latin, r):
+			counts[LanguageEnglish]++
+		}
+	}
+	
+	// Kana is specific to Japanese while Kanji is shared with Chinese
+	if counts[LanguageJapanese] > 0 {
+		counts[LanguageJapanese] += counts[LanguageChinese]
+		delete(counts, LanguageChinese)
+	}
+	
+	detected, best := LanguageEnglish, 0
+	for l, count := range counts {
+		if count > best || (count == best && l < detected) {
+			detected, best = l, count
+		}
+	}
+	return detected, nil
+}
+
+// detectPromptLanguage returns the language of non-English prompts, empty when English or undetected
+func (sdk *SDK) detectPromptLanguage(ctx context.Context, text string) (Language, error) {
+	if sdk.languageDetector == nil || text == "" {
+		return "", nil
+	}
+	
+	l, err := sdk.languageDetector.DetectLanguage(ctx, text)
+	if err != nil {
+		return "", err
+	}
+	if l == LanguageEnglish || !l.Valid() {
+		return "", nil
+	}
+	return l, nil
+}
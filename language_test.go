@@ -0,0 +1,118 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptLanguageDetector(t *testing.T) {
+	tests := []struct {
+		text string
+		want Language
+	}{
+		{text: "A beautiful landscape", want: LanguageEnglish},
+		{text: "Красивый пейзаж", want: LanguageRussian},
+		{text: "美しい風景", want: LanguageJapanese},
+		{text: "美丽的风景", want: LanguageChinese},
+		{text: "아름다운 풍경", want: LanguageKorean},
+		{text: "منظر طبيعي جميل", want: LanguageArabic},
+		{text: "", want: LanguageEnglish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ScriptLanguageDetector{}.DetectLanguage(context.Background(), tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLanguageFromCode(t *testing.T) {
+	l, err := LanguageFromCode("pt-BR")
+	assert.NoError(t, err)
+	assert.Equal(t, LanguagePortuguese, l)
+	assert.Equal(t, "pt", l.Code())
+	
+	_, err = LanguageFromCode("xx")
+	assert.ErrorIs(t, err, ErrFieldIncorrectVal)
+}
+
+func TestNewImagePromptLanguageDetection(t *testing.T) {
+	incoming := make(chan []byte, 1)
+	var sent map[string]NewTaskReq
+	
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				_ = json.Unmarshal(b, &sent)
+				incoming <- []byte(`{"newImages":{"images":[{"imageUUID":"img-1","taskUUID":"task-1"}]}}`)
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+		languageDetector: LanguageDetectorFunc(func(ctx context.Context, text string) (Language, error) {
+			return LanguageSpanish, nil
+		}),
+	}
+	
+	_, err := sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "Un paisaje hermoso",
+		NumberResults: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, LanguageSpanish, *sent[NewTask].PromptLanguageId)
+	
+	invalid := Language("xx")
+	_, err = sdk.NewImage(context.Background(), NewTaskReq{
+		PromptText:       "Un paisaje hermoso",
+		PromptLanguageId: &invalid,
+	})
+	assert.ErrorIs(t, err, ErrFieldIncorrectVal)
+}
+
+func TestLanguageJSON(t *testing.T) {
+	lang := LanguageSpanish
+	b, err := json.Marshal(NewTaskReq{PromptLanguageId: &lang})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"promptLanguageId":"es"`)
+	
+	var req NewTaskReq
+	assert.NoError(t, json.Unmarshal([]byte(`{"promptLanguageId":"pt-BR"}`), &req))
+	assert.Equal(t, LanguagePortuguese, *req.PromptLanguageId)
+	
+	assert.Error(t, json.Unmarshal([]byte(`{"promptLanguageId":6}`), &req))
+}
+
+func TestPromptEnhancerPromptLanguage(t *testing.T) {
+	server := newFakeServer()
+	handleFake(server, NewPromptEnhance, func(req NewPromptEnhanceReq) error {
+		server.reply(NewPromptEnhancer, NewPromptEnhanceRes{Texts: []Text{{TaskUUID: req.TaskUUID, Text: "Un gato"}}})
+		return nil
+	})
+	sdk := server.sdk()
+	sdk.languageDetector = LanguageDetectorFunc(func(ctx context.Context, text string) (Language, error) {
+		return LanguageSpanish, nil
+	})
+	
+	_, err := sdk.PromptEnhancer(context.Background(), NewPromptEnhanceReq{PromptText: "Un gato"})
+	assert.NoError(t, err)
+	
+	sent := receivedFake[NewPromptEnhanceReq](server, NewPromptEnhance)
+	if assert.Len(t, sent, 1) {
+		assert.Equal(t, LanguageSpanish, *sent[0].PromptLanguageId)
+	}
+	
+	invalid := Language("xx")
+	_, err = sdk.PromptEnhancer(context.Background(), NewPromptEnhanceReq{
+		PromptText:       "Un gato",
+		PromptLanguageId: &invalid,
+	})
+	assert.ErrorIs(t, err, ErrFieldIncorrectVal)
+	assert.Len(t, receivedFake[NewPromptEnhanceReq](server, NewPromptEnhance), 1)
+}
@@ -0,0 +1,142 @@
+package runware
+
+import (
+	"sync"
+	"time"
+)
+
+// LifecycleEventType kind of connection lifecycle event
+type LifecycleEventType int
+
+const (
+	// EventConnecting the SDK starts connecting
+	EventConnecting LifecycleEventType = iota + 1
+	// EventConnected the session is opened, SessionUUID is set
+	EventConnected
+	// EventDisconnected the connection was lost, Err holds the cause
+	EventDisconnected
+	// EventReconnecting a reconnection attempt starts, Attempt is set
+	EventReconnecting
+	// EventReconnected the connection is restored, the session is resumed next
+	EventReconnected
+	// EventSessionResumed the session is resumed after a reconnection, SessionUUID is set
+	EventSessionResumed
+	// EventClosed the client was closed
+	EventClosed
+)
+
+var lifecycleEventNames = map[LifecycleEventType]string{
+	EventConnecting:     "connecting",
+	EventConnected:      "connected",
+	EventDisconnected:   "disconnected",
+	EventReconnecting:   "reconnecting",
+	EventReconnected:    "reconnected",
+	EventSessionResumed: "session_resumed",
+	EventClosed:         "closed",
+}
+
+func (t LifecycleEventType) String() string {
+	return lifecycleEventNames[t]
+}
+
+// LifecycleEvent connection lifecycle event
+type LifecycleEvent struct {
+	Type        LifecycleEventType
+	Time        time.Time
+	SessionUUID string
+	Attempt     int
+	Err         error
+}
+
+// lifecycle broadcasts the events to the config hook and the subscribers
+type lifecycle struct {
+	hook func(LifecycleEvent)
+	
+	mu          sync.Mutex
+	subs        map[chan LifecycleEvent]struct{}
+	connectedAt time.Time
+	reconnects  int
+}
+
+func newLifecycle(hook func(LifecycleEvent)) *lifecycle {
+	return &lifecycle{
+		hook: hook,
+		subs: make(map[chan LifecycleEvent]struct{}),
+	}
+}
+
+// emit sends the event, subscribers not keeping up miss it
+func (l *lifecycle) emit(ev LifecycleEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if l.hook != nil {
+		l.hook(ev)
+	}
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	switch ev.Type {
+	case EventConnected, EventSessionResumed:
+		l.connectedAt = ev.Time
+	case EventReconnected:
+		l.reconnects++
+	}
+	
+	for sub := range l.subs {
+		select {
+		case sub <- ev:
+		default:
+		}
+	}
+}
+
+// stats returns when the session was last opened and the number of reconnections
+func (l *lifecycle) stats() (time.Time, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	return l.connectedAt, l.reconnects
+}
+
+func (l *lifecycle) subscribe(buffer int) (<-chan LifecycleEvent, func()) {
+	sub := make(chan LifecycleEvent, buffer)
+	
+	l.mu.Lock()
+	l.subs[sub] = struct{}{}
+	l.mu.Unlock()
+	
+	var once sync.Once
+	return sub, func() {
+		once.Do(func() {
+			l.mu.Lock()
+			delete(l.subs, sub)
+			l.mu.Unlock()
+			close(sub)
+		})
+	}
+}
+
+// Subscribe returns a channel of the lifecycle events and a function to unsubscribe. Events are
+// dropped when the channel buffer is full. Use SDKConfig.OnLifecycle to observe the first connection
+func (sdk *SDK) Subscribe(buffer int) (<-chan LifecycleEvent, func()) {
+	return sdk.lifecycle().subscribe(buffer)
+}
+
+// lifecycle returns the SDK lifecycle events, created on first use for SDKs not built by NewSDK
+func (sdk *SDK) lifecycle() *lifecycle {
+	sdk.eventsOnce.Do(func() {
+		if sdk.events == nil {
+			sdk.events = newLifecycle(nil)
+		}
+	})
+	return sdk.events
+}
+
+// emitLifecycle calls the hook when set
+func emitLifecycle(hook func(LifecycleEvent), ev LifecycleEvent) {
+	if hook != nil {
+		hook(ev)
+	}
+}
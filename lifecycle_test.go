@@ -0,0 +1,143 @@
+package runware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventRecorder records lifecycle events
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []LifecycleEvent
+}
+
+func (r *eventRecorder) record(ev LifecycleEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func (r *eventRecorder) types() []LifecycleEventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	types := make([]LifecycleEventType, 0, len(r.events))
+	for _, ev := range r.events {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+func TestLifecycleSubscribe(t *testing.T) {
+	sdk := &SDK{}
+	events, unsubscribe := sdk.Subscribe(1)
+	
+	sdk.lifecycle().emit(LifecycleEvent{Type: EventDisconnected})
+	sdk.lifecycle().emit(LifecycleEvent{Type: EventReconnecting, Attempt: 1})
+	
+	// The subscriber buffer is full, the second event is dropped
+	ev := <-events
+	assert.Equal(t, EventDisconnected, ev.Type)
+	assert.False(t, ev.Time.IsZero())
+	assert.Equal(t, "disconnected", ev.Type.String())
+	
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestLifecycleSDK(t *testing.T) {
+	var (
+		incoming    = make(chan []byte, 1)
+		reconnected = make(chan struct{})
+		recorder    = &eventRecorder{}
+	)
+	
+	client := &MockRunware{
+		SendFunc: func(b []byte) error {
+			var msg map[string]NewConnectReq
+			_ = json.Unmarshal(b, &msg)
+			if msg[NewConnection].ConnectionSessionUUID == "" {
+				incoming <- []byte(`{"newConnectionSessionUUID":{"connectionSessionUUID":"session-1"}}`)
+			} else {
+				incoming <- []byte(`{"newConnectionSessionUUID":{"connectionSessionUUID":"` + msg[NewConnection].ConnectionSessionUUID + `"}}`)
+			}
+			return nil
+		},
+		ListenFunc: func() chan []byte {
+			return incoming
+		},
+		ReconnectedFunc: func() chan struct{} {
+			return reconnected
+		},
+	}
+	
+	sdk, err := NewSDK(SDKConfig{Client: client, OnLifecycle: recorder.record})
+	assert.NoError(t, err)
+	assert.Equal(t, []LifecycleEventType{EventConnecting, EventConnected}, recorder.types())
+	
+	events, unsubscribe := sdk.Subscribe(4)
+	defer unsubscribe()
+	
+	reconnected <- struct{}{}
+	select {
+	case ev := <-events:
+		assert.Equal(t, EventSessionResumed, ev.Type)
+		assert.Equal(t, "session-1", ev.SessionUUID)
+	case <-time.After(time.Second):
+		t.Fatal("session not resumed")
+	}
+}
+
+func TestLifecycleWebSocket(t *testing.T) {
+	var (
+		upgrader = websocket.Upgrader{}
+		conns    = make(chan *websocket.Conn, 2)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conns <- conn
+	}))
+	defer server.Close()
+	
+	recorder := &eventRecorder{}
+	client, err := New(RunwareConfig{
+		APIKey:      "key",
+		ConnAddr:    ConnAddr("ws" + strings.TrimPrefix(server.URL, "http")),
+		OnLifecycle: recorder.record,
+	})
+	assert.NoError(t, err)
+	
+	// The server drops the connection, the client reconnects
+	first := <-conns
+	_ = first.Close()
+	
+	select {
+	case <-client.Reconnected():
+	case <-time.After(2 * time.Second):
+		t.Fatal("not reconnected")
+	}
+	second := <-conns
+	defer second.Close()
+	
+	assert.NoError(t, client.Close())
+	assert.NoError(t, client.Close())
+	assert.Equal(t, []LifecycleEventType{EventDisconnected, EventReconnecting, EventReconnected, EventClosed}, recorder.types())
+	
+	// Closing doesn't trigger a reconnection
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, conns, 0)
+	
+}
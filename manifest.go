@@ -0,0 +1,83 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	
+	"github.com/google/uuid"
+)
+
+const manifestVersion = 1
+
+// Manifest captures what was sent to generate images, so they can be regenerated later
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Request fully resolved task request, after defaults and middlewares were applied
+	Request NewTaskReq `json:"request"`
+	// Images as reported by the server
+	Images []Image `json:"images,omitempty"`
+}
+
+func newManifest(req NewTaskReq) *Manifest {
+	return &Manifest{
+		Version:   manifestVersion,
+		CreatedAt: time.Now().UTC(),
+		Request:   req,
+	}
+}
+
+// ParseManifest decodes a JSON encoded manifest
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	
+	if m.Version == 0 || m.Version > manifestVersion {
+		return nil, fmt.Errorf("%w:[%s][%d]", ErrFieldIncorrectVal, "version", m.Version)
+	}
+	
+	return &m, nil
+}
+
+// ForImage narrows the manifest to a single image, using the image seed. Images of tasks with
+// several results can't be reproduced alone when the server did not report their seed
+func (m Manifest) ForImage(imageUUID string) (*Manifest, error) {
+	for _, img := range m.Images {
+		if img.ImageUUID != imageUUID {
+			continue
+		}
+		
+		if img.Seed == 0 && m.Request.NumberResults != 1 {
+			return nil, fmt.Errorf("%w:[%s][%s]", ErrFieldRequired, "seed", imageUUID)
+		}
+		
+		imgManifest := m
+		imgManifest.Images = []Image{img}
+		imgManifest.Request.NumberResults = 1
+		imgManifest.Request.Offset = 0
+		if img.Seed != 0 {
+			imgManifest.Request.Seed = img.Seed
+		}
+		return &imgManifest, nil
+	}
+	
+	return nil, fmt.Errorf("%w:[%s][%s]", ErrFieldIncorrectVal, "imageUUID", imageUUID)
+}
+
+// NewTaskReq returns the manifest request ready to be submitted again with a new TaskUUID
+func (m Manifest) NewTaskReq() NewTaskReq {
+	req := m.Request
+	req.TaskUUID = uuid.New().String()
+	req.Lora = append([]Lora(nil), m.Request.Lora...)
+	req.ControlNet = append([]ControlNet(nil), m.Request.ControlNet...)
+	return req
+}
+
+// Regenerate submits the manifest request again
+func (sdk *SDK) Regenerate(ctx context.Context, m Manifest) (*NewTaskResp, error) {
+	return sdk.NewImage(ctx, m.NewTaskReq())
+}
@@ -0,0 +1,94 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest(t *testing.T) {
+	incoming := make(chan []byte, 1)
+	var sent map[string]NewTaskReq
+	
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				_ = json.Unmarshal(b, &sent)
+				incoming <- []byte(`{"newImages":{"images":[{"imageUUID":"img-1","taskUUID":"task-1","seed":77}]}}`)
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+	}
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "A beautiful landscape",
+		NumberResults: 1,
+		Lora:          []Lora{{ModelID: "lora-1", Weight: 0.5}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, sent[NewTask], resp.Manifest.Request)
+	assert.Equal(t, resp.Images, resp.Manifest.Images)
+	
+	b, err := json.Marshal(resp.Manifest)
+	assert.NoError(t, err)
+	
+	manifest, err := ParseManifest(b)
+	assert.NoError(t, err)
+	
+	imgManifest, err := manifest.ForImage("img-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(77), imgManifest.Request.Seed)
+	assert.Equal(t, 1, imgManifest.Request.NumberResults)
+	
+	req := imgManifest.NewTaskReq()
+	assert.NotEqual(t, "task-1", req.TaskUUID)
+	assert.Equal(t, sent[NewTask].Lora, req.Lora)
+	
+	_, err = manifest.ForImage("unknown")
+	assert.ErrorIs(t, err, ErrFieldIncorrectVal)
+}
+
+func TestManifestForImageMultipleResults(t *testing.T) {
+	incoming := make(chan []byte, 1)
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				incoming <- []byte(`{"newImages":{"images":[` +
+					`{"imageUUID":"img-1","taskUUID":"task-1","seed":77},` +
+					`{"imageUUID":"img-2","taskUUID":"task-1","seed":78},` +
+					`{"imageUUID":"img-3","taskUUID":"task-1"}]}}`)
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+	}
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "A beautiful landscape",
+		NumberResults: 3,
+		Seed:          1234,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1236), resp.Images[2].Seed)
+	
+	for i, seed := range []int64{77, 78, 1236} {
+		imgManifest, err := resp.Manifest.ForImage(resp.Images[i].ImageUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, seed, imgManifest.Request.Seed)
+		assert.Equal(t, 1, imgManifest.Request.NumberResults)
+	}
+	
+	// Manifests without the seed of an image can't reproduce it alone
+	resp.Manifest.Images[2].Seed = 0
+	_, err = resp.Manifest.ForImage("img-3")
+	assert.ErrorIs(t, err, ErrFieldRequired)
+}
@@ -0,0 +1,87 @@
+package runware
+
+import (
+	"context"
+)
+
+// Middleware hooks into the lifecycle of every SDK request. Any of the hooks can be left nil.
+type Middleware struct {
+	// BeforeSend is called before the request is encoded and sent.
+	// The request can be mutated in place, returning an error aborts it.
+	BeforeSend func(ctx context.Context, req *Request) error
+	
+	// AfterReceive is called for every incoming frame before it is dispatched to the request.
+	// Returning a nil frame drops it.
+	AfterReceive func(ctx context.Context, req Request, msg []byte) ([]byte, error)
+	
+	// OnError is called when the request fails. A non-nil returned error replaces the original one.
+	OnError func(ctx context.Context, req Request, err error) error
+}
+
+// Use appends middlewares to the chain. Middlewares are executed in the order they were added.
+// It's safe to call while requests are in flight, they keep the chain they started with
+func (sdk *SDK) Use(mw ...Middleware) {
+	sdk.middlewaresMu.Lock()
+	defer sdk.middlewaresMu.Unlock()
+	
+	// Copy on write, the running requests iterate over the previous chain
+	chain := make([]Middleware, 0, len(sdk.middlewares)+len(mw))
+	chain = append(chain, sdk.middlewares...)
+	sdk.middlewares = append(chain, mw...)
+}
+
+func (sdk *SDK) middlewareChain() []Middleware {
+	sdk.middlewaresMu.RLock()
+	defer sdk.middlewaresMu.RUnlock()
+	
+	return sdk.middlewares
+}
+
+func (sdk *SDK) beforeSend(ctx context.Context, req *Request) error {
+	for _, mw := range sdk.middlewareChain() {
+		if mw.BeforeSend == nil {
+			continue
+		}
+		if err := mw.BeforeSend(ctx, req); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (sdk *SDK) afterReceive(ctx context.Context, req Request, msg []byte) ([]byte, error) {
+	var err error
+	for _, mw := range sdk.middlewareChain() {
+		if mw.AfterReceive == nil {
+			continue
+		}
+		
+		msg, err = mw.AfterReceive(ctx, req, msg)
+		if err != nil {
+			return nil, err
+		}
+		if msg == nil {
+			return nil, nil
+		}
+	}
+	
+	return msg, nil
+}
+
+func (sdk *SDK) onRequestError(ctx context.Context, req Request, err error) error {
+	if err == nil {
+		return nil
+	}
+	
+	for _, mw := range sdk.middlewareChain() {
+		if mw.OnError == nil {
+			continue
+		}
+		if mwErr := mw.OnError(ctx, req, err); mwErr != nil {
+			err = mwErr
+		}
+	}
+	
+	return err
+}
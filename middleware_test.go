@@ -0,0 +1,84 @@
+package runware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	server := newFakeServer().answerImages(1)
+	sdk := server.sdk()
+	
+	var received int
+	sdk.Use(Middleware{
+		BeforeSend: func(ctx context.Context, req *Request) error {
+			taskReq := req.Data.(NewTaskReq)
+			taskReq.ModelId = ModelSDXL.String()
+			req.Data = taskReq
+			return nil
+		},
+		AfterReceive: func(ctx context.Context, req Request, msg []byte) ([]byte, error) {
+			received++
+			return msg, nil
+		},
+	})
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "A beautiful landscape",
+		NumberResults: 1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Images, 1)
+	assert.Equal(t, 1, received)
+	
+	assert.Equal(t, ModelSDXL.String(), receivedFake[NewTaskReq](server, NewTask)[0].ModelId)
+}
+
+func TestMiddlewareOnError(t *testing.T) {
+	errBlocked := errors.New("blocked prompt")
+	errWrapped := errors.New("wrapped")
+	
+	sdk := newFakeServer().sdk()
+	sdk.Use(Middleware{
+		BeforeSend: func(ctx context.Context, req *Request) error {
+			return errBlocked
+		},
+	}, Middleware{
+		OnError: func(ctx context.Context, req Request, err error) error {
+			assert.ErrorIs(t, err, errBlocked)
+			return errWrapped
+		},
+	})
+	
+	_, err := sdk.NewImage(context.Background(), NewTaskReq{
+		PromptText: "A beautiful landscape",
+	})
+	assert.ErrorIs(t, err, errWrapped)
+}
+
+func TestMiddlewareUseConcurrently(t *testing.T) {
+	sdk := newFakeServer().answerEcho(2).sdk()
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := Do(context.Background(), sdk, echoOperation(fmt.Sprintf("task-%d", i)))
+			assert.NoError(t, err)
+		}(i)
+		go func() {
+			defer wg.Done()
+			sdk.Use(Middleware{})
+		}()
+	}
+	wg.Wait()
+	
+	assert.Len(t, sdk.middlewareChain(), 10)
+}
@@ -0,0 +1,265 @@
+package runware
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ModelID identifier of a Runware model
+type ModelID int
+
+func (id ModelID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+// Architecture base architecture a model is built on
+type Architecture string
+
+const (
+	ArchitectureSD15 Architecture = "SD1.5"
+	ArchitectureSDXL Architecture = "SDXL"
+)
+
+var (
+	sd15SizeIds = []int{
+		SizeSquare512,
+		SizePortrait2to3,
+		SizePortrait1to2,
+		SizeLandscape2to3,
+		SizeLandscape2to1,
+		SizeLandscape4to3,
+		SizeLandscape16to9,
+		SizePortrait9to16,
+		SizePortrait3to4,
+	}
+	sdxlSizeIds = []int{
+		SizeSquare1024SDXL,
+		SizeLandscape16to9SDXL,
+		SizePortrait9to16SDXL,
+		SizePortrait2to3SDXL,
+		SizeLandscape3to2SDXL,
+	}
+	imageTaskTypes = []int{
+		TextToImage,
+		ImageToImage,
+		Inpainting,
+		ControlNetTextToImage,
+		ControlNetImageToImage,
+		ControlNetPreprocessImage,
+	}
+)
+
+// ModelSettings default task settings applied when a model is used
+type ModelSettings struct {
+	SizeId    int
+	Steps     int
+	CFGScale  float64
+	Scheduler Scheduler
+}
+
+// Model describes a model and its capabilities
+type Model struct {
+	ID           ModelID
+	Name         string
+	Architecture Architecture
+	SizeIds      []int
+	TaskTypes    []int
+	SupportsLora bool
+	Defaults     ModelSettings
+	// MinPixels and MaxPixels bound the pixel count of custom width/height dimensions
+	MinPixels int
+	MaxPixels int
+	// CivitAIModelID identifier of the model on CivitAI, 0 when not published there
+	CivitAIModelID int
+	// CivitAIVersionID identifier of the model version on CivitAI, 0 when unknown
+	CivitAIVersionID int
+}
+
+// AIR returns the AI Resource identifier of the model (`civitai:<model>@<version>`). It fails with
+// ErrModelWithoutAIR when the model or its version is not known on CivitAI
+func (m Model) AIR() (string, error) {
+	if m.CivitAIModelID == 0 || m.CivitAIVersionID == 0 {
+		return "", fmt.Errorf("%w:[%s]", ErrModelWithoutAIR, m.Name)
+	}
+	return fmt.Sprintf("civitai:%d@%d", m.CivitAIModelID, m.CivitAIVersionID), nil
+}
+
+// clone returns a copy of the model which doesn't share the slices of the catalog
+func (m Model) clone() Model {
+	m.SizeIds = slices.Clone(m.SizeIds)
+	m.TaskTypes = slices.Clone(m.TaskTypes)
+	return m
+}
+
+// SupportsSize reports whether sizeId is available for the model
+func (m Model) SupportsSize(sizeId int) bool {
+	return slices.Contains(m.SizeIds, sizeId)
+}
+
+// SupportsTaskType reports whether the model can run the task type
+func (m Model) SupportsTaskType(taskType int) bool {
+	return slices.Contains(m.TaskTypes, taskType)
+}
+
+var (
+	modelsMu sync.RWMutex
+	models   = []Model{
+		newSDXLModel(ModelSDXL, "SDXL", 101055, 128078),
+		newSD15Model(ModelRevAnimated, "ReV Animated", 7371, 46846),
+		newSD15Model(ModelAbsolutereality, "AbsoluteReality", 81458, 132760),
+		newSD15Model(ModelCyberrealistic, "CyberRealistic", 15003, 0),
+		newSD15Model(ModelDreamshaper, "DreamShaper", 4384, 128713),
+		newSD15Model(ModelGhostmixBakedvae, "GhostMix BakedVAE", 36520, 76907),
+		newSD15Model(ModelSamaritan3DCartoon, "Samaritan 3D Cartoon", 81270, 0),
+	}
+)
+
+func newSD15Model(id ModelID, name string, civitAIModelID, civitAIVersionID int) Model {
+	return Model{
+		ID:               id,
+		Name:             name,
+		Architecture:     ArchitectureSD15,
+		SizeIds:          sd15SizeIds,
+		TaskTypes:        imageTaskTypes,
+		SupportsLora:     true,
+		MinPixels:        256 * 256,
+		MaxPixels:        1024 * 1024,
+		CivitAIModelID:   civitAIModelID,
+		CivitAIVersionID: civitAIVersionID,
+		Defaults: ModelSettings{
+			SizeId:    SizeSquare512,
+			Steps:     20,
+			CFGScale:  7,
+			Scheduler: SchedulerDPMSolverMultistep,
+		},
+	}
+}
+
+func newSDXLModel(id ModelID, name string, civitAIModelID, civitAIVersionID int) Model {
+	return Model{
+		ID:               id,
+		Name:             name,
+		Architecture:     ArchitectureSDXL,
+		SizeIds:          sdxlSizeIds,
+		TaskTypes:        imageTaskTypes,
+		SupportsLora:     true,
+		MinPixels:        512 * 512,
+		MaxPixels:        2048 * 2048,
+		CivitAIModelID:   civitAIModelID,
+		CivitAIVersionID: civitAIVersionID,
+		Defaults: ModelSettings{
+			SizeId:    SizeSquare1024SDXL,
+			Steps:     30,
+			CFGScale:  7,
+			Scheduler: SchedulerEuler,
+		},
+	}
+}
+
+// Models returns the models catalog
+func Models() []Model {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+	
+	res := make([]Model, 0, len(models))
+	for _, m := range models {
+		res = append(res, m.clone())
+	}
+	return res
+}
+
+// RegisterModel adds a model to the catalog or replaces the one with the same ID
+func RegisterModel(m Model) {
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	
+	m = m.clone()
+	for idx, existing := range models {
+		if existing.ID == m.ID {
+			models[idx] = m
+			return
+		}
+	}
+	models = append(models, m)
+}
+
+// LookupModel finds a catalog model by its name, case-insensitive
+func LookupModel(name string) (Model, bool) {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+	
+	for _, m := range models {
+		if strings.EqualFold(m.Name, strings.TrimSpace(name)) {
+			return m.clone(), true
+		}
+	}
+	return Model{}, false
+}
+
+// ModelByID finds a catalog model by its ID
+func ModelByID(id ModelID) (Model, bool) {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+	
+	for _, m := range models {
+		if m.ID == id {
+			return m.clone(), true
+		}
+	}
+	return Model{}, false
+}
+
+// modelFromTaskModelId resolves the catalog model of a task `modelId`
+func modelFromTaskModelId(modelId string) (Model, bool) {
+	id, err := strconv.Atoi(modelId)
+	if err != nil {
+		return Model{}, false
+	}
+	return ModelByID(ModelID(id))
+}
+
+// applyModelDefaults fills the unset task settings with the model defaults
+func applyModelDefaults(req *NewTaskReq, model Model) {
+	if req.SizeId == 0 && !hasCustomDimensions(*req) {
+		req.SizeId = model.Defaults.SizeId
+	}
+	if req.Steps == 0 {
+		req.Steps = model.Defaults.Steps
+	}
+	if req.CFGScale == 0 {
+		req.CFGScale = model.Defaults.CFGScale
+	}
+	if req.Scheduler == "" {
+		req.Scheduler = model.Defaults.Scheduler
+	}
+}
+
+// validateModelCompatibility rejects task settings unsupported by a catalog model.
+// Models outside the catalog are not validated
+func validateModelCompatibility(req NewTaskReq) error {
+	model, ok := modelFromTaskModelId(req.ModelId)
+	if !ok {
+		return nil
+	}
+	
+	if req.SizeId != 0 && !model.SupportsSize(req.SizeId) {
+		return fmt.Errorf("%w:[%s][sizeId:%d]", ErrModelIncompatible, model.Name, req.SizeId)
+	}
+	
+	if pixels := req.Width * req.Height; pixels != 0 && (pixels < model.MinPixels || pixels > model.MaxPixels) {
+		return fmt.Errorf("%w:[%s][width*height:%d][%d-%d]", ErrModelIncompatible, model.Name, pixels, model.MinPixels, model.MaxPixels)
+	}
+	
+	if req.TaskType != 0 && !model.SupportsTaskType(req.TaskType) {
+		return fmt.Errorf("%w:[%s][taskType:%d]", ErrModelIncompatible, model.Name, req.TaskType)
+	}
+	
+	if len(req.Lora) > 0 && !model.SupportsLora {
+		return fmt.Errorf("%w:[%s][lora]", ErrModelIncompatible, model.Name)
+	}
+	
+	return nil
+}
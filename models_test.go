@@ -0,0 +1,90 @@
+package runware
+
+import (
+	"errors"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupModel(t *testing.T) {
+	model, ok := LookupModel("absolutereality")
+	assert.True(t, ok)
+	assert.Equal(t, ModelAbsolutereality, model.ID)
+	assert.Equal(t, ArchitectureSD15, model.Architecture)
+	air, err := model.AIR()
+	assert.NoError(t, err)
+	assert.Equal(t, "civitai:81458@132760", air)
+	
+	// The CivitAI version of the model is unknown
+	model, ok = LookupModel("CyberRealistic")
+	assert.True(t, ok)
+	_, err = model.AIR()
+	assert.ErrorIs(t, err, ErrModelWithoutAIR)
+	
+	_, ok = LookupModel("unknown")
+	assert.False(t, ok)
+}
+
+func TestValidateModelCompatibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     NewTaskReq
+		wantErr error
+	}{
+		{
+			name:    "SD1.5 with SD1.5 size",
+			req:     NewTaskReq{ModelId: ModelDreamshaper.String(), SizeId: SizePortrait2to3},
+			wantErr: nil,
+		},
+		{
+			name:    "SD1.5 with SDXL size",
+			req:     NewTaskReq{ModelId: ModelDreamshaper.String(), SizeId: SizeSquare1024SDXL},
+			wantErr: ErrModelIncompatible,
+		},
+		{
+			name:    "SDXL with SD1.5 size",
+			req:     NewTaskReq{ModelId: ModelSDXL.String(), SizeId: SizeSquare512},
+			wantErr: ErrModelIncompatible,
+		},
+		{
+			name:    "Model outside catalog",
+			req:     NewTaskReq{ModelId: "civitai:4384@128713", SizeId: SizeSquare1024SDXL},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateModelCompatibility(tt.req)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tt.wantErr))
+		})
+	}
+}
+
+func TestMergeNewTaskReqModelDefaults(t *testing.T) {
+	req := mergeNewTaskReqWithDefaults(&NewTaskReq{
+		PromptText: "A beautiful landscape",
+		ModelId:    ModelSDXL.String(),
+	})
+	assert.Equal(t, SizeSquare1024SDXL, req.SizeId)
+	assert.NoError(t, validateModelCompatibility(*req))
+	
+	// Requests without model get the defaults of the default model
+	req = mergeNewTaskReqWithDefaults(&NewTaskReq{PromptText: "A beautiful landscape"})
+	assert.Equal(t, ModelAbsolutereality.String(), req.ModelId)
+	assert.Equal(t, SchedulerDPMSolverMultistep, req.Scheduler)
+}
+
+func TestModelsAreCopies(t *testing.T) {
+	model := Models()[0]
+	sizeId := model.SizeIds[0]
+	model.SizeIds[0] = -1
+	
+	model, ok := ModelByID(model.ID)
+	assert.True(t, ok)
+	assert.Equal(t, sizeId, model.SizeIds[0])
+}
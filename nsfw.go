@@ -0,0 +1,119 @@
+package runware
+
+import (
+	"fmt"
+)
+
+// NSFWPolicy how images flagged as NSFW by the server are handled
+type NSFWPolicy int
+
+const (
+	// NSFWDefault uses the SDK policy, which defaults to NSFWAllow
+	NSFWDefault NSFWPolicy = iota
+	// NSFWAllow returns NSFW images untouched
+	NSFWAllow
+	// NSFWFlag returns NSFW images and reports their count
+	NSFWFlag
+	// NSFWDrop removes NSFW images from the response and reports their count
+	NSFWDrop
+	// NSFWFail fails the request with ErrNSFWContent when any image is NSFW
+	NSFWFail
+)
+
+// resolveNSFWPolicy returns the request policy or the SDK one when not set
+func (sdk *SDK) resolveNSFWPolicy(policy NSFWPolicy) NSFWPolicy {
+	if policy != NSFWDefault {
+		return policy
+	}
+	if sdk.nsfwPolicy != NSFWDefault {
+		return sdk.nsfwPolicy
+	}
+	return NSFWAllow
+}
+
+// applyNSFWPolicy returns the images to keep with the number of flagged and filtered ones
+func applyNSFWPolicy(policy NSFWPolicy, images []Image) ([]Image, int, int, error) {
+	if policy == NSFWAllow || policy == NSFWDefault {
+		return images, 0, 0, nil
+	}
+	
+	var (
+		kept    = make([]Image, 0, len(images))
+		flagged = 0
+	)
+	for _, img := range images {
+		if !img.BNSFWContent {
+			kept = append(kept, img)
+			continue
+		}
+		
+		flagged++
+		if policy == NSFWFail {
+			return nil, flagged, 0, fmt.Errorf("%w:[%s]", ErrNSFWContent, img.ImageUUID)
+		}
+		if policy == NSFWFlag {
+			kept = append(kept, img)
+		}
+	}
+	
+	if policy == NSFWDrop {
+		return kept, 0, flagged, nil
+	}
+	return kept, flagged, 0, nil
+}
+
+func (r *NewTaskResp) applyNSFWPolicy(policy NSFWPolicy) error {
+	images, flagged, filtered, err := applyNSFWPolicy(policy, r.Images)
+	if err != nil {
+		return err
+	}
+	
+	r.Images = images
+	r.NSFWFlagged = flagged
+	r.NSFWFiltered = filtered
+	if r.Manifest != nil {
+		r.Manifest.Images = r.Images
+	}
+	return nil
+}
+
+// Results returns the number of results returned by the server, including the ones dropped by the NSFW policy.
+// Pages follow one another by this count
+func (r *NewTaskResp) Results() int {
+	return len(r.Images) + r.NSFWFiltered
+}
+
+func (r *NewUpscaleGanResp) applyNSFWPolicy(policy NSFWPolicy) error {
+	images, flagged, filtered, err := applyNSFWPolicy(policy, r.Images)
+	if err != nil {
+		return err
+	}
+	
+	r.Images = images
+	r.NSFWFlagged = flagged
+	r.NSFWFiltered = filtered
+	return nil
+}
+
+func (r *NewControlNetsResp) applyNSFWPolicy(policy NSFWPolicy) error {
+	if r.NNsfwContent == nil {
+		return nil
+	}
+	
+	images, flagged, filtered, err := applyNSFWPolicy(policy, []Image{{
+		ImageSrc:     r.NewImageSrc,
+		ImageUUID:    r.NewImageUUID,
+		BNSFWContent: *r.NNsfwContent,
+	}})
+	if err != nil {
+		return err
+	}
+	
+	if len(images) == 0 {
+		r.NewImageSrc = ""
+		r.NewImageUUID = ""
+	}
+	r.NSFWFlagged = flagged
+	r.NSFWFiltered = filtered
+	return nil
+}
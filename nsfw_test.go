@@ -0,0 +1,86 @@
+package runware
+
+import (
+	"context"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyNSFWPolicy(t *testing.T) {
+	images := []Image{
+		{ImageUUID: "img-1"},
+		{ImageUUID: "img-2", BNSFWContent: true},
+		{ImageUUID: "img-3"},
+	}
+	
+	tests := []struct {
+		name         string
+		policy       NSFWPolicy
+		wantImages   int
+		wantFlagged  int
+		wantFiltered int
+		wantErr      error
+	}{
+		{name: "Default", policy: NSFWDefault, wantImages: 3},
+		{name: "Allow", policy: NSFWAllow, wantImages: 3},
+		{name: "Flag", policy: NSFWFlag, wantImages: 3, wantFlagged: 1},
+		{name: "Drop", policy: NSFWDrop, wantImages: 2, wantFiltered: 1},
+		{name: "Fail", policy: NSFWFail, wantErr: ErrNSFWContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, flagged, filtered, err := applyNSFWPolicy(tt.policy, images)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, kept, tt.wantImages)
+			assert.Equal(t, tt.wantFlagged, flagged)
+			assert.Equal(t, tt.wantFiltered, filtered)
+		})
+	}
+}
+
+func TestNewImageNSFWPolicy(t *testing.T) {
+	incoming := make(chan []byte, 1)
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				incoming <- []byte(`{"newImages":{"images":[{"imageUUID":"img-1","taskUUID":"task-1"},{"imageUUID":"img-2","taskUUID":"task-1","bNSFWContent":true}]}}`)
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+		nsfwPolicy: NSFWDrop,
+	}
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+		Seed:          1234,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Image{{ImageUUID: "img-1", TaskUUID: "task-1", Seed: 1234}}, resp.Images)
+	assert.Equal(t, resp.Images, resp.Manifest.Images)
+	assert.Equal(t, 1, resp.NSFWFiltered)
+	
+	_, err = sdk.NewImage(context.Background(), NewTaskReq{
+		TaskUUID:      "task-1",
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+		NSFWPolicy:    NSFWFail,
+	})
+	assert.ErrorIs(t, err, ErrNSFWContent)
+}
+
+func TestNewTaskRespResults(t *testing.T) {
+	resp := &NewTaskResp{Images: []Image{{ImageUUID: "img-1"}, {ImageUUID: "img-2", BNSFWContent: true}}}
+	assert.NoError(t, resp.applyNSFWPolicy(NSFWDrop))
+	assert.Len(t, resp.Images, 1)
+	assert.Equal(t, 2, resp.Results())
+}
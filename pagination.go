@@ -0,0 +1,136 @@
+package runware
+
+import (
+	"context"
+	"fmt"
+	
+	"github.com/google/uuid"
+)
+
+// HasMore reports whether the task has results beyond the ones returned
+func (r *NewTaskResp) HasMore() bool {
+	if r.Manifest == nil {
+		return false
+	}
+	return r.Manifest.Request.Offset+r.Results() < r.TotalAvailableResults
+}
+
+// FetchPage requests results of a prior task starting at offset. The request is usually taken
+// from the task Manifest, its TaskUUID identifies the task
+func (sdk *SDK) FetchPage(ctx context.Context, req NewTaskReq, offset int) (*NewTaskResp, error) {
+	if req.TaskUUID == "" {
+		return nil, fmt.Errorf("%w:[%s]", ErrFieldRequired, "taskUUID")
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w:[%s][>=0]", ErrFieldIncorrectVal, "offset")
+	}
+	
+	req.Offset = offset
+	return sdk.NewImage(ctx, req)
+}
+
+// NextPage requests the results following resp
+func (sdk *SDK) NextPage(ctx context.Context, resp *NewTaskResp) (*NewTaskResp, error) {
+	if !resp.HasMore() {
+		return nil, ErrNoMoreResults
+	}
+	
+	req := resp.Manifest.Request
+	offset := req.Offset + resp.Results()
+	req.NumberResults = min(req.NumberResults, resp.TotalAvailableResults-offset)
+	
+	page, err := sdk.FetchPage(ctx, req, offset)
+	if err != nil {
+		cancelPending(page)
+	}
+	return page, err
+}
+
+// cancelPending stops waiting for the remaining images of a failed page
+func cancelPending(page *NewTaskResp) {
+	if page != nil && page.Pending != nil {
+		page.Pending.Cancel()
+	}
+}
+
+// ImageIterator pages through all the available results of a task
+//
+//	it := sdk.IterateImages(req)
+//	for it.Next(ctx) {
+//		page := it.Page()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type ImageIterator struct {
+	sdk    *SDK
+	req    NewTaskReq
+	offset int
+	total  int
+	page   *NewTaskResp
+	err    error
+	done   bool
+}
+
+// IterateImages returns an iterator over the results of req, NumberResults is used as page size
+func (sdk *SDK) IterateImages(req NewTaskReq) *ImageIterator {
+	// All pages must share the task UUID
+	if req.TaskUUID == "" {
+		req.TaskUUID = uuid.New().String()
+	}
+	
+	return &ImageIterator{
+		sdk:    sdk,
+		req:    req,
+		offset: req.Offset,
+		total:  -1,
+	}
+}
+
+// Next fetches the next page, it returns false when all results were fetched or on error
+func (it *ImageIterator) Next(ctx context.Context) bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	
+	req := it.req
+	if it.total >= 0 {
+		req.NumberResults = min(req.NumberResults, it.total-it.offset)
+	}
+	
+	it.page, it.err = it.sdk.FetchPage(ctx, req, it.offset)
+	if it.err != nil {
+		// The iteration stops, the images of a timed out page are not waited for
+		cancelPending(it.page)
+		return false
+	}
+	
+	// A page can be emptied by the NSFW policy, the iteration goes on
+	if it.page.Results() == 0 {
+		it.done = true
+		return false
+	}
+	
+	// Following pages are sent with the resolved request (e.g. the picked seed)
+	if it.page.Manifest != nil {
+		it.req = it.page.Manifest.Request
+	}
+	
+	it.offset += it.page.Results()
+	it.total = it.page.TotalAvailableResults
+	if it.offset >= it.total {
+		it.done = true
+	}
+	
+	return true
+}
+
+// Page returns the last fetched page
+func (it *ImageIterator) Page() *NewTaskResp {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration
+func (it *ImageIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,138 @@
+package runware
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageIterator(t *testing.T) {
+	server := newFakeServer().answerImages(5)
+	sdk := server.sdk()
+	
+	it := sdk.IterateImages(NewTaskReq{
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+	})
+	
+	var images []string
+	for it.Next(context.Background()) {
+		for _, img := range it.Page().Images {
+			images = append(images, img.ImageUUID)
+		}
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"img-0", "img-1", "img-2", "img-3", "img-4"}, images)
+	
+	sent := receivedFake[NewTaskReq](server, NewTask)
+	assert.Len(t, sent, 3)
+	assert.Equal(t, []int{0, 2, 4}, []int{sent[0].Offset, sent[1].Offset, sent[2].Offset})
+	assert.Equal(t, 1, sent[2].NumberResults)
+	for _, req := range sent {
+		assert.Equal(t, sent[0].TaskUUID, req.TaskUUID)
+		assert.Equal(t, sent[0].Seed, req.Seed)
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	sdk := newFakeServer().answerImages(3).sdk()
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+	})
+	assert.NoError(t, err)
+	assert.True(t, resp.HasMore())
+	
+	resp, err = sdk.NextPage(context.Background(), resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Images, 1)
+	assert.False(t, resp.HasMore())
+	
+	_, err = sdk.NextPage(context.Background(), resp)
+	assert.ErrorIs(t, err, ErrNoMoreResults)
+}
+
+func TestImageIteratorNSFWDrop(t *testing.T) {
+	server := newFakeServer().answerImages(4, 0)
+	sdk := server.sdk()
+	sdk.nsfwPolicy = NSFWDrop
+	
+	it := sdk.IterateImages(NewTaskReq{
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+	})
+	
+	var images []string
+	for it.Next(context.Background()) {
+		for _, img := range it.Page().Images {
+			images = append(images, img.ImageUUID)
+		}
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"img-1", "img-2", "img-3"}, images)
+	
+	// Dropped images still move the offset
+	sent := receivedFake[NewTaskReq](server, NewTask)
+	assert.Len(t, sent, 2)
+	assert.Equal(t, []int{0, 2}, []int{sent[0].Offset, sent[1].Offset})
+	
+	resp, err := sdk.NewImage(context.Background(), NewTaskReq{
+		PromptText:    "A beautiful landscape",
+		NumberResults: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Images, 1)
+	assert.True(t, resp.HasMore())
+	
+	resp, err = sdk.NextPage(context.Background(), resp)
+	assert.NoError(t, err)
+	sent = receivedFake[NewTaskReq](server, NewTask)
+	assert.Len(t, sent, 4)
+	assert.Equal(t, 2, sent[3].Offset)
+	assert.False(t, resp.HasMore())
+}
+
+func TestPagesTimeout(t *testing.T) {
+	server := newFakeServer()
+	handleFake(server, NewTask, func(req NewTaskReq) error {
+		// A single image of the page arrives
+		server.reply(NewImage, NewTaskResp{
+			TotalAvailableResults: 4,
+			Images:                []Image{{ImageUUID: fmt.Sprintf("img-%d", req.Offset), TaskUUID: req.TaskUUID}},
+		})
+		return nil
+	})
+	sdk := server.sdk()
+	sdk.timeout = 50 * time.Millisecond
+	
+	it := sdk.IterateImages(NewTaskReq{PromptText: "A beautiful landscape", NumberResults: 2})
+	assert.False(t, it.Next(context.Background()))
+	assert.ErrorIs(t, it.Err(), ErrRequestTimeout)
+	assertPendingCancelled(t, it.Page())
+	
+	resp := &NewTaskResp{
+		TotalAvailableResults: 4,
+		Images:                []Image{{ImageUUID: "img-0"}, {ImageUUID: "img-1"}},
+		Manifest:              newManifest(NewTaskReq{TaskUUID: "task-1", PromptText: "A beautiful landscape", NumberResults: 2}),
+	}
+	page, err := sdk.NextPage(context.Background(), resp)
+	assert.ErrorIs(t, err, ErrRequestTimeout)
+	assert.Len(t, page.Images, 1)
+	assertPendingCancelled(t, page)
+}
+
+func assertPendingCancelled(t *testing.T, page *NewTaskResp) {
+	t.Helper()
+	
+	if assert.NotNil(t, page) && assert.NotNil(t, page.Pending) {
+		select {
+		case <-page.Pending.Done():
+		case <-time.After(time.Second):
+			t.Error("pending images still waited for")
+		}
+	}
+}
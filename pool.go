@@ -0,0 +1,595 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultPoolSize           = 2
+	defaultPoolHealthInterval = 10 * time.Second
+)
+
+// Pool Runware client spreading the tasks over several sessions. Each task is sent on the
+// session with the fewest tasks in flight, a task counts as in flight until the SDK reports its end
+// (final frame, error, cancellation or timeout), or its error frame.
+// Follow-up messages of a task (e.g. pages, abort) stick to its session. Sessions failing to send
+// or losing their connection are replaced in the background
+type Pool struct {
+	cfg         PoolConfig
+	onLifecycle func(LifecycleEvent)
+	incoming    chan []byte
+	replace     chan struct{}
+	done        chan struct{}
+	
+	mu      sync.Mutex
+	members []*poolMember
+	tasks   map[string]*poolTask
+	closed  bool
+}
+
+type poolMember struct {
+	client      Runware
+	sessionUUID string
+	inFlight    int
+	healthy     bool
+	
+	sendMu      sync.Mutex
+	handshaking atomic.Bool
+	handshake   chan []byte
+	stop        chan struct{}
+}
+
+type poolTask struct {
+	member *poolMember
+	// ended tasks are kept for their follow-up messages, they don't count as in flight
+	ended  bool
+	sentAt time.Time
+}
+
+// PoolMemberStats state of a pool session
+type PoolMemberStats struct {
+	SessionUUID string
+	InFlight    int
+	Healthy     bool
+}
+
+// NewPool connects the pool sessions
+func NewPool(cfg PoolConfig) (*Pool, error) {
+	if cfg.APIKey == "" {
+		return nil, ErrApiKeyRequired
+	}
+	if cfg.Size < 1 {
+		cfg.Size = defaultPoolSize
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = defaultPoolHealthInterval
+	}
+	if cfg.Dial == nil {
+		cfg.Dial = New
+	}
+	
+	p := &Pool{
+		cfg:         cfg,
+		onLifecycle: cfg.OnLifecycle,
+		incoming:    make(chan []byte),
+		replace:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		tasks:       make(map[string]*poolTask),
+	}
+	
+	for i := 0; i < cfg.Size; i++ {
+		m, err := p.dial()
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+		p.members = append(p.members, m)
+	}
+	
+	go p.healthLoop()
+	
+	return p, nil
+}
+
+func (p *Pool) APIKey() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	return p.cfg.APIKey
+}
+
+// Reauthenticate restores the sessions with the new key, it's used by the sessions dialed later on.
+// The previous key is kept when the first session is refused
+func (p *Pool) Reauthenticate(ctx context.Context, apiKey string) error {
+	p.mu.Lock()
+	previous := p.cfg.APIKey
+	p.cfg.APIKey = apiKey
+	members := make([]*poolMember, 0, len(p.members))
+	for _, m := range p.members {
+		if m.healthy {
+			members = append(members, m)
+		}
+	}
+	p.mu.Unlock()
+	
+	for i, m := range members {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.connect(m); err != nil {
+			if i == 0 {
+				p.mu.Lock()
+				p.cfg.APIKey = previous
+				p.mu.Unlock()
+				return err
+			}
+			// The key was accepted, the session is replaced
+			log.Println("Pool session re-authentication failed", err)
+			p.markUnhealthy(m)
+		}
+	}
+	return nil
+}
+
+// Connected reports whether a session is healthy
+func (p *Pool) Connected() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	for _, m := range p.members {
+		if m.healthy {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes all sessions
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	members := p.members
+	p.mu.Unlock()
+	
+	close(p.done)
+	for _, m := range members {
+		m.close()
+	}
+	emitLifecycle(p.onLifecycle, LifecycleEvent{Type: EventClosed})
+	return nil
+}
+
+// Send sends the message on a healthy session
+func (p *Pool) Send(msg []byte) error {
+	if msg == nil {
+		return ErrOutgoingIsNil
+	}
+	
+	var frame map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &frame); err != nil {
+		return fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	
+	// Sessions are opened by the pool, the SDK gets the first one
+	if _, ok := frame[NewConnection]; ok {
+		return p.answerConnection()
+	}
+	var taskUUID string
+	for _, v := range frame {
+		if taskUUID = frameTaskUUID(v); taskUUID != "" {
+			break
+		}
+	}
+	
+	for {
+		m := p.assign(taskUUID)
+		if m == nil {
+			return fmt.Errorf("%w:[%s]", ErrWsDial, "no healthy connection in pool")
+		}
+		
+		err := m.send(msg)
+		if err == nil {
+			return nil
+		}
+		
+		log.Println("Pool session send failed", err)
+		p.unassign(taskUUID, m)
+		p.markUnhealthy(m)
+	}
+}
+
+func (p *Pool) Listen() chan []byte {
+	return p.incoming
+}
+
+// Reconnected never fires, the pool restores the sessions of reconnected clients itself
+func (p *Pool) Reconnected() chan struct{} {
+	return nil
+}
+
+// Stats returns the state of the pool sessions
+func (p *Pool) Stats() []PoolMemberStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	stats := make([]PoolMemberStats, 0, len(p.members))
+	for _, m := range p.members {
+		stats = append(stats, PoolMemberStats{
+			SessionUUID: m.sessionUUID,
+			InFlight:    m.inFlight,
+			Healthy:     m.healthy,
+		})
+	}
+	return stats
+}
+
+func (p *Pool) answerConnection() error {
+	p.mu.Lock()
+	var sessionUUID string
+	for _, m := range p.members {
+		if m.healthy {
+			sessionUUID = m.sessionUUID
+			break
+		}
+	}
+	p.mu.Unlock()
+	
+	if sessionUUID == "" {
+		return fmt.Errorf("%w:[%s]", ErrWsDial, "no healthy connection in pool")
+	}
+	
+	go p.deliver([]byte(fmt.Sprintf(`{%q:{"connectionSessionUUID":%q}}`, NewConnectionSessionUUID, sessionUUID)))
+	return nil
+}
+
+// assign returns the session of the task, or the healthy one with the fewest tasks in flight
+func (p *Pool) assign(taskUUID string) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if task, ok := p.tasks[taskUUID]; ok {
+		if task.member.healthy {
+			// Follow-up messages count the task in flight again
+			if task.ended {
+				task.ended = false
+				task.sentAt = time.Now()
+				task.member.inFlight++
+			}
+			return task.member
+		}
+		
+		// The task moves to a healthy session
+		if !task.ended {
+			task.member.inFlight--
+		}
+		delete(p.tasks, taskUUID)
+	}
+	
+	var best *poolMember
+	for _, m := range p.members {
+		if m.healthy && (best == nil || m.inFlight < best.inFlight) {
+			best = m
+		}
+	}
+	if best == nil || taskUUID == "" {
+		return best
+	}
+	
+	best.inFlight++
+	p.tasks[taskUUID] = &poolTask{member: best, sentAt: time.Now()}
+	return best
+}
+
+func (p *Pool) unassign(taskUUID string, m *poolMember) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if task, ok := p.tasks[taskUUID]; ok && task.member == m {
+		if !task.ended {
+			m.inFlight--
+		}
+		delete(p.tasks, taskUUID)
+	}
+}
+
+// received ends the task of an error frame, the other frames don't tell whether the task is complete
+func (p *Pool) received(msg []byte) {
+	var msgData map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &msgData); err != nil || !isErrorFrame(msgData) {
+		return
+	}
+	
+	var errData struct {
+		TaskUUID string `json:"taskUUID"`
+	}
+	_ = json.Unmarshal(msg, &errData)
+	p.releaseTask(errData.TaskUUID)
+}
+
+// releaseTask stops counting the task in flight, its session is kept for follow-up messages
+func (p *Pool) releaseTask(taskUUID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if task, ok := p.tasks[taskUUID]; ok && !task.ended {
+		task.ended = true
+		task.member.inFlight--
+	}
+}
+
+func (p *Pool) markUnhealthy(m *poolMember) {
+	p.mu.Lock()
+	m.healthy = false
+	p.mu.Unlock()
+	
+	select {
+	case p.replace <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pool) deliver(msg []byte) {
+	select {
+	case p.incoming <- msg:
+	case <-p.done:
+	}
+}
+
+// healthLoop replaces the unhealthy sessions and forgets the old tasks
+func (p *Pool) healthLoop() {
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		case <-p.replace:
+		}
+		
+		p.replaceUnhealthy()
+		p.pruneTasks()
+	}
+}
+
+func (p *Pool) replaceUnhealthy() {
+	p.mu.Lock()
+	var unhealthy []*poolMember
+	for _, m := range p.members {
+		if !m.healthy {
+			unhealthy = append(unhealthy, m)
+		}
+	}
+	p.mu.Unlock()
+	
+	for _, old := range unhealthy {
+		m, err := p.dial()
+		if err != nil {
+			log.Println("Pool session replacement failed", err)
+			return
+		}
+		
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			m.close()
+			return
+		}
+		for i := range p.members {
+			if p.members[i] == old {
+				p.members[i] = m
+			}
+		}
+		for taskUUID, task := range p.tasks {
+			if task.member == old {
+				delete(p.tasks, taskUUID)
+			}
+		}
+		log.Println("Pool session replaced", old.sessionUUID, m.sessionUUID)
+		p.mu.Unlock()
+		
+		old.close()
+	}
+}
+
+func (p *Pool) pruneTasks() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	for taskUUID, task := range p.tasks {
+		if time.Since(task.sentAt) > pendingCallTTL {
+			if !task.ended {
+				task.member.inFlight--
+			}
+			delete(p.tasks, taskUUID)
+		}
+	}
+}
+
+// dial connects a new session
+func (p *Pool) dial() (*poolMember, error) {
+	p.mu.Lock()
+	cfg := p.cfg.RunwareConfig
+	p.mu.Unlock()
+	
+	m := &poolMember{
+		handshake: make(chan []byte, 1),
+		stop:      make(chan struct{}),
+	}
+	cfg.OnLifecycle = p.memberLifecycle(m)
+	
+	client, err := p.cfg.Dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.client = client
+	go p.readLoop(m)
+	
+	if err = p.connect(m); err != nil {
+		m.close()
+		return nil, err
+	}
+	
+	p.mu.Lock()
+	m.healthy = true
+	p.mu.Unlock()
+	
+	go p.reconnectLoop(m)
+	
+	return m, nil
+}
+
+// memberLifecycle forwards the member events, members losing their connection are replaced.
+// The pool reports its own close
+func (p *Pool) memberLifecycle(m *poolMember) func(LifecycleEvent) {
+	return func(ev LifecycleEvent) {
+		if ev.Type == EventClosed {
+			return
+		}
+		if ev.Type == EventDisconnected {
+			p.markUnhealthy(m)
+			if errors.Is(ev.Err, ErrWsReconnect) {
+				ev.Err = fmt.Errorf("%w:[%s]", ErrWsDial, "session replaced")
+			}
+		}
+		emitLifecycle(p.onLifecycle, ev)
+	}
+}
+
+// connect opens the session of the member, or restores it when already opened
+func (p *Pool) connect(m *poolMember) error {
+	m.handshaking.Store(true)
+	defer m.handshaking.Store(false)
+	
+	p.mu.Lock()
+	sessionUUID := m.sessionUUID
+	apiKey := p.cfg.APIKey
+	p.mu.Unlock()
+	
+	msg, err := json.Marshal(map[string]NewConnectReq{
+		NewConnection: {
+			APIKey:                apiKey,
+			ConnectionSessionUUID: sessionUUID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = m.send(msg); err != nil {
+		return err
+	}
+	
+	select {
+	case msg = <-m.handshake:
+	case <-time.After(timeoutSendResponse * time.Second):
+		return fmt.Errorf("%w:[%s]", ErrRequestTimeout, NewConnection)
+	case <-p.done:
+		return fmt.Errorf("%w:[%s]", ErrWsDial, "pool closed")
+	}
+	
+	var msgData map[string]interface{}
+	if err = json.Unmarshal(msg, &msgData); err != nil {
+		return fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	if errMsg, ok := parseErrorFrame(msgData); ok {
+		return errMsg
+	}
+	
+	var resp map[string]NewConnectResp
+	if err = json.Unmarshal(msg, &resp); err != nil {
+		return fmt.Errorf("%w:[%s]", ErrDecodeMessage, err.Error())
+	}
+	p.mu.Lock()
+	m.sessionUUID = resp[NewConnectionSessionUUID].ConnectionSessionUUID
+	p.mu.Unlock()
+	return nil
+}
+
+// readLoop forwards the member messages to the pool
+func (p *Pool) readLoop(m *poolMember) {
+	incoming := m.client.Listen()
+	for {
+		select {
+		case msg, ok := <-incoming:
+			if !ok {
+				p.markUnhealthy(m)
+				return
+			}
+			if m.handshaking.Load() && isHandshakeFrame(msg) {
+				select {
+				case m.handshake <- msg:
+				default:
+				}
+				continue
+			}
+			
+			p.received(msg)
+			select {
+			case p.incoming <- msg:
+			case <-p.done:
+				return
+			case <-m.stop:
+				return
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// reconnectLoop restores the session when the member client reconnected
+func (p *Pool) reconnectLoop(m *poolMember) {
+	for {
+		select {
+		case <-m.client.Reconnected():
+			if err := p.connect(m); err != nil {
+				log.Println("Pool session restore failed", err)
+				p.markUnhealthy(m)
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (m *poolMember) send(msg []byte) error {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+	
+	return m.client.Send(msg)
+}
+
+func (m *poolMember) close() {
+	select {
+	case <-m.stop:
+		return
+	default:
+		close(m.stop)
+	}
+	_ = m.client.Close()
+}
+
+// isHandshakeFrame reports whether the message answers a session request
+func isHandshakeFrame(msg []byte) bool {
+	var msgData map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &msgData); err != nil {
+		return false
+	}
+	if _, ok := msgData[NewConnectionSessionUUID]; ok {
+		return true
+	}
+	_, hasTask := msgData["taskUUID"]
+	return isErrorFrame(msgData) && !hasTask
+}
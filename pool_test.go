@@ -0,0 +1,250 @@
+package runware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePoolSession session answering the connection, tasks are answered through respond
+type fakePoolSession struct {
+	id          int
+	incoming    chan []byte
+	failSend    atomic.Bool
+	onLifecycle func(LifecycleEvent)
+	
+	mu      sync.Mutex
+	tasks   []string
+	apiKeys []string
+}
+
+func (s *fakePoolSession) Send(b []byte) error {
+	if s.failSend.Load() {
+		return errors.New("broken pipe")
+	}
+	
+	var msg map[string]map[string]interface{}
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	if connect, ok := msg[NewConnection]; ok {
+		s.mu.Lock()
+		s.apiKeys = append(s.apiKeys, connect["apiKey"].(string))
+		s.mu.Unlock()
+		
+		go func() {
+			s.incoming <- []byte(fmt.Sprintf(`{"newConnectionSessionUUID":{"connectionSessionUUID":"session-%d"}}`, s.id))
+		}()
+		return nil
+	}
+	
+	for _, data := range msg {
+		s.mu.Lock()
+		s.tasks = append(s.tasks, data["taskUUID"].(string))
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *fakePoolSession) respond(taskUUID string) {
+	s.incoming <- []byte(fmt.Sprintf(`{"newImages":{"images":[{"imageUUID":"img","taskUUID":%q}]}}`, taskUUID))
+}
+
+func (s *fakePoolSession) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.tasks...)
+}
+
+func newTestPool(t *testing.T, size int) (*Pool, func(i int) *fakePoolSession) {
+	var (
+		mu       sync.Mutex
+		sessions []*fakePoolSession
+	)
+	
+	pool, err := NewPool(PoolConfig{
+		RunwareConfig:       RunwareConfig{APIKey: "key"},
+		Size:                size,
+		HealthCheckInterval: 10 * time.Millisecond,
+		Dial: func(cfg RunwareConfig) (Runware, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			
+			s := &fakePoolSession{id: len(sessions), incoming: make(chan []byte, 10), onLifecycle: cfg.OnLifecycle}
+			sessions = append(sessions, s)
+			return &MockRunware{
+				SendFunc:   s.Send,
+				ListenFunc: func() chan []byte { return s.incoming },
+			}, nil
+		},
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = pool.Close() })
+	
+	return pool, func(i int) *fakePoolSession {
+		mu.Lock()
+		defer mu.Unlock()
+		if i >= len(sessions) {
+			return nil
+		}
+		return sessions[i]
+	}
+}
+
+func TestPoolBalancing(t *testing.T) {
+	pool, session := newTestPool(t, 2)
+	
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, pool.Send([]byte(fmt.Sprintf(`{"newTask":{"taskUUID":"task-%d"}}`, i))))
+	}
+	assert.Len(t, session(0).sent(), 2)
+	assert.Len(t, session(1).sent(), 2)
+	
+	// Ended tasks free their session
+	for _, taskUUID := range session(0).sent() {
+		session(0).respond(taskUUID)
+		<-pool.Listen()
+		pool.releaseTask(taskUUID)
+	}
+	stats := pool.Stats()
+	assert.Equal(t, 0, stats[0].InFlight)
+	assert.Equal(t, 2, stats[1].InFlight)
+	
+	assert.NoError(t, pool.Send([]byte(`{"newTask":{"taskUUID":"task-4"}}`)))
+	assert.Contains(t, session(0).sent(), "task-4")
+	
+	// Follow-up messages go to the session of their task
+	task1Session := session(0)
+	if !contains(task1Session.sent(), "task-1") {
+		task1Session = session(1)
+	}
+	assert.NoError(t, pool.Send([]byte(`{"abort":{"taskUUID":"task-1"}}`)))
+	sent := task1Session.sent()
+	assert.Equal(t, "task-1", sent[len(sent)-1])
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPoolReplacesUnhealthySessions(t *testing.T) {
+	pool, session := newTestPool(t, 2)
+	
+	session(0).failSend.Store(true)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, pool.Send([]byte(fmt.Sprintf(`{"newTask":{"taskUUID":"task-%d"}}`, i))))
+	}
+	assert.Len(t, session(1).sent(), 3)
+	
+	assert.Eventually(t, func() bool {
+		return session(2) != nil && pool.Stats()[0].Healthy
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "session-2", pool.Stats()[0].SessionUUID)
+	
+	session(1).failSend.Store(true)
+	session(2).failSend.Store(true)
+	assert.Error(t, pool.Send([]byte(`{"newTask":{"taskUUID":"task-3"}}`)))
+}
+
+func TestPoolReplacesDisconnectedSessions(t *testing.T) {
+	pool, session := newTestPool(t, 2)
+	
+	session(0).onLifecycle(LifecycleEvent{Type: EventDisconnected, Err: errors.New("connection reset")})
+	
+	assert.Eventually(t, func() bool {
+		return session(2) != nil && pool.Stats()[0].Healthy
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "session-2", pool.Stats()[0].SessionUUID)
+	assert.Equal(t, "session-1", pool.Stats()[1].SessionUUID)
+}
+
+func TestPoolSDK(t *testing.T) {
+	pool, session := newTestPool(t, 3)
+	
+	sdk, err := NewSDK(SDKConfig{Client: pool})
+	assert.NoError(t, err)
+	assert.Equal(t, "session-0", sdk.sessionKey)
+	
+	go func() {
+		for i := 0; i < 3; i++ {
+			assert.Eventually(t, func() bool { return len(session(i).sent()) > 0 }, time.Second, time.Millisecond)
+			for _, taskUUID := range session(i).sent() {
+				session(i).respond(taskUUID)
+			}
+		}
+	}()
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := sdk.NewImage(context.Background(), NewTaskReq{PromptText: "A cat", NumberResults: 1})
+			assert.NoError(t, err)
+			assert.Len(t, resp.Images, 1)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPoolInFlightUntilTaskEnds(t *testing.T) {
+	pool, session := newTestPool(t, 2)
+	
+	sdk, err := NewSDK(SDKConfig{Client: pool})
+	assert.NoError(t, err)
+	
+	call, err := Start(context.Background(), sdk, Operation[NewTaskReq, NewTaskResp]{
+		Event:         NewTask,
+		ResponseEvent: NewImage,
+		TaskUUID:      "task-0",
+		Request:       NewTaskReq{TaskUUID: "task-0", PromptText: "A cat", NumberResults: 2},
+		Merge: func(req NewTaskReq, resp *NewTaskResp, frame *NewTaskResp) bool {
+			resp.Images = append(resp.Images, frame.Images...)
+			return len(resp.Images) >= req.NumberResults
+		},
+	})
+	assert.NoError(t, err)
+	
+	// The first frame doesn't end the task
+	session(0).respond("task-0")
+	assert.Eventually(t, func() bool { return len(call.Response().Images) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, pool.Stats()[0].InFlight)
+	
+	session(0).respond("task-0")
+	_, err = call.Wait(context.Background(), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pool.Stats()[0].InFlight)
+}
+
+func TestPoolMovesTasksOfUnhealthySessions(t *testing.T) {
+	pool, session := newTestPool(t, 2)
+	
+	assert.NoError(t, pool.Send([]byte(`{"newTask":{"taskUUID":"task-0"}}`)))
+	assert.Contains(t, session(0).sent(), "task-0")
+	
+	pool.mu.Lock()
+	old := pool.members[0]
+	old.healthy = false
+	pool.mu.Unlock()
+	
+	// The retried task leaves the unhealthy session
+	assert.NoError(t, pool.Send([]byte(`{"newTask":{"taskUUID":"task-0"}}`)))
+	assert.Contains(t, session(1).sent(), "task-0")
+	
+	pool.mu.Lock()
+	assert.Equal(t, 0, old.inFlight)
+	assert.Equal(t, 1, pool.tasks["task-0"].member.inFlight)
+	pool.mu.Unlock()
+}
@@ -1,9 +1,12 @@
 package runware
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 	
 	"github.com/gorilla/websocket"
@@ -24,6 +27,9 @@ const (
 	pongWait            = 5 * time.Second
 	pingInterval        = (pongWait * 9) / 10
 	timeoutSendResponse = 30 // In sec
+	
+	defaultConnectAttempts = 3
+	connectRetryDelay      = time.Second
 )
 
 type Runware interface {
@@ -39,8 +45,15 @@ type runware struct {
 	apiKey           string
 	sessionKey       string
 	connStr          ConnAddr
+	dialOptions      DialOptions
+	clientMu         sync.Mutex
 	client           *websocket.Conn
+	writeMu          sync.Mutex
 	incomingMessages chan []byte
+	onLifecycle      func(LifecycleEvent)
+	closed           atomic.Bool
+	disconnected     atomic.Bool
+	sessionMu        sync.Mutex
 	
 	reconnectAttempt int
 	reconnectChan    chan struct{}
@@ -48,24 +61,52 @@ type runware struct {
 }
 
 func (r *runware) APIKey() string {
+	r.sessionMu.Lock()
+	defer r.sessionMu.Unlock()
+	
 	return r.apiKey
 }
 
+// SetAPIKey replaces the key after the session was re-authenticated
+func (r *runware) SetAPIKey(apiKey string) {
+	r.sessionMu.Lock()
+	defer r.sessionMu.Unlock()
+	
+	r.apiKey = apiKey
+}
+
 func (r *runware) Connected() bool {
-	if r.client == nil {
+	if r.conn() == nil || r.closed.Load() || r.disconnected.Load() {
 		return false
 	}
 	
-	if r.sessionKey == "" {
-		return false
-	}
+	r.sessionMu.Lock()
+	defer r.sessionMu.Unlock()
 	
-	return true
+	return r.sessionKey != ""
 }
 
-// Close connection to socket
+// Close connection to socket, it isn't reconnected afterwards
 func (r *runware) Close() error {
-	return r.client.Close()
+	if r.closed.Swap(true) {
+		return nil
+	}
+	
+	err := r.closeConn()
+	emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventClosed})
+	return err
+}
+
+func (r *runware) closeConn() error {
+	return r.conn().Close()
+}
+
+// conn returns the current connection, it's replaced on reconnection
+func (r *runware) conn() *websocket.Conn {
+	r.clientMu.Lock()
+	defer r.clientMu.Unlock()
+	
+	return r.client
 }
 
 // Send socket message
@@ -74,7 +115,11 @@ func (r *runware) Send(msg []byte) error {
 		return ErrOutgoingIsNil
 	}
 	
-	return r.client.WriteMessage(websocket.TextMessage, msg)
+	// Connections support a single concurrent writer
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	
+	return r.conn().WriteMessage(websocket.TextMessage, msg)
 }
 
 func (r *runware) Listen() chan []byte {
@@ -85,6 +130,19 @@ func (r *runware) Reconnected() chan struct{} {
 	return r.reconnectedChan
 }
 
+// setSession keeps the session UUID of the connection response
+func (r *runware) setSession(data json.RawMessage) {
+	var resp NewConnectResp
+	if err := json.Unmarshal(data, &resp); err != nil || resp.ConnectionSessionUUID == "" {
+		return
+	}
+	
+	r.sessionMu.Lock()
+	defer r.sessionMu.Unlock()
+	
+	r.sessionKey = resp.ConnectionSessionUUID
+}
+
 func (r *runware) handleSendAndResponseError(msg map[string]interface{}) (error, bool) {
 	var (
 		hasError       = false
@@ -120,8 +178,12 @@ func (r *runware) heartbeatLoop() {
 		select {
 		case <-ticker.C:
 			log.Println("Ping ...")
-			if err := r.Send([]byte(`{"ping": true}`)); err != nil {
+			if r.closed.Load() {
+				return
+			}
+			if err := r.Send([]byte(`{"` + Ping + `": true}`)); err != nil {
 				log.Println("Ping err", err)
+				emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventDisconnected, Err: err})
 				r.reconnectAttempt = 1
 				r.reconnectChan <- struct{}{}
 			}
@@ -131,8 +193,9 @@ func (r *runware) heartbeatLoop() {
 
 // readLoop incoming message monitoring
 func (r *runware) readLoop() {
+	conn := r.conn()
 	defer func() {
-		_ = r.Close()
+		_ = conn.Close()
 	}()
 	
 	// TODO: This deadline causes unexpected connection close
@@ -143,8 +206,14 @@ func (r *runware) readLoop() {
 	// })
 	
 	for {
-		_, msg, err := r.client.ReadMessage()
+		_, msg, err := conn.ReadMessage()
 		if err != nil {
+			if r.closed.Load() {
+				break
+			}
+			r.disconnected.Store(true)
+			emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventDisconnected, Err: err})
+			
 			ok := websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure)
 			if ok {
 				log.Println("Abnormal close", err)
@@ -158,11 +227,10 @@ func (r *runware) readLoop() {
 			break
 		}
 		
-		var msgData map[string]interface{}
+		var msgData map[string]json.RawMessage
 		_ = json.Unmarshal(msg, &msgData)
-		if _, ok := msgData[Pong]; ok {
-			log.Println("Pong", msgData[Pong])
-			continue
+		if session, ok := msgData[NewConnectionSessionUUID]; ok {
+			r.setSession(session)
 		}
 		
 		fmt.Printf("[readLoop]: %+v\n", string(msg))
@@ -181,25 +249,36 @@ func (r *runware) reconnectLoop() {
 			log.Println("Reconnecting to runware...")
 			
 			for i := 0; i < r.reconnectAttempt; i++ {
-				var err error
-				_ = r.Close()
-				r.client, err = wsConnect(r.connStr.String())
+				if r.closed.Load() {
+					return
+				}
+				emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventReconnecting, Attempt: i + 1})
+				
+				_ = r.closeConn()
+				client, err := wsConnect(context.Background(), r.connStr.String(), r.dialOptions)
 				if err != nil {
 					log.Printf("Reconnect attempt %d failed: %s\n", i+1, err.Error())
 					time.Sleep(5 * time.Second)
 					continue
 				}
 				
+				r.clientMu.Lock()
+				r.client = client
+				r.clientMu.Unlock()
+				r.disconnected.Store(false)
+				
 				// Restart loops
 				go r.readLoop()
 				go r.reconnectLoop()
 				
+				emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventReconnected, Attempt: i + 1})
 				r.reconnectedChan <- struct{}{}
 				fmt.Printf("Attempt: %d\n", i+1)
 				return
 			}
 			
 			log.Println("Reconnection failed after 3 attempts. Aborted")
+			emitLifecycle(r.onLifecycle, LifecycleEvent{Type: EventDisconnected, Err: fmt.Errorf("%w:%w", ErrWsDial, ErrWsReconnect)})
 			return
 		}
 	}
@@ -207,6 +286,11 @@ func (r *runware) reconnectLoop() {
 
 // New create a new client and initiate connection
 func New(cfg RunwareConfig) (Runware, error) {
+	return NewContext(context.Background(), cfg)
+}
+
+// NewContext create a new client, ctx bounds the initial connection
+func NewContext(ctx context.Context, cfg RunwareConfig) (Runware, error) {
 	
 	if cfg.APIKey == "" {
 		return nil, ErrApiKeyRequired
@@ -216,7 +300,7 @@ func New(cfg RunwareConfig) (Runware, error) {
 		cfg.ConnAddr = ProdEnv
 	}
 	
-	client, err := wsConnect(cfg.ConnAddr.String())
+	client, err := wsConnect(ctx, cfg.ConnAddr.String(), cfg.DialOptions)
 	if err != nil {
 		return nil, fmt.Errorf("%w:[%s]", ErrWsDial, cfg.ConnAddr.String())
 	}
@@ -224,6 +308,8 @@ func New(cfg RunwareConfig) (Runware, error) {
 	r := &runware{
 		apiKey:           cfg.APIKey,
 		connStr:          cfg.ConnAddr,
+		dialOptions:      cfg.DialOptions,
+		onLifecycle:      cfg.OnLifecycle,
 		client:           client,
 		incomingMessages: make(chan []byte),
 		reconnectChan:    make(chan struct{}),
@@ -250,7 +336,29 @@ func interfaceToByte(v interface{}) ([]byte, error) {
 	}
 }
 
-func wsConnect(connStr string) (*websocket.Conn, error) {
-	conn, _, err := websocket.DefaultDialer.Dial(connStr, nil)
-	return conn, err
+func wsConnect(ctx context.Context, connStr string, opts DialOptions) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		Proxy:             opts.Proxy,
+		TLSClientConfig:   opts.TLSConfig,
+		HandshakeTimeout:  opts.HandshakeTimeout,
+		ReadBufferSize:    opts.ReadBufferSize,
+		WriteBufferSize:   opts.WriteBufferSize,
+		EnableCompression: opts.EnableCompression,
+	}
+	if dialer.Proxy == nil {
+		dialer.Proxy = websocket.DefaultDialer.Proxy
+	}
+	if dialer.HandshakeTimeout == 0 {
+		dialer.HandshakeTimeout = websocket.DefaultDialer.HandshakeTimeout
+	}
+	
+	conn, _, err := dialer.DialContext(ctx, connStr, opts.Header)
+	if err != nil {
+		return nil, err
+	}
+	
+	if opts.MaxMessageSize > 0 {
+		conn.SetReadLimit(opts.MaxMessageSize)
+	}
+	return conn, nil
 }
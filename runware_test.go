@@ -0,0 +1,83 @@
+package runware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newWsServer returns a TLS websocket server echoing messages, the handshake requests are sent to headers
+func newWsServer(t *testing.T, headers chan http.Header) *httptest.Server {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		
+		for {
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err = conn.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWsConnectDialOptions(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := newWsServer(t, headers)
+	
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	
+	var proxied atomic.Int32
+	opts := DialOptions{
+		Proxy: func(r *http.Request) (*url.URL, error) {
+			proxied.Add(1)
+			return nil, nil
+		},
+		TLSConfig:         &tls.Config{RootCAs: pool},
+		Header:            http.Header{"User-Agent": {"runware-test"}},
+		EnableCompression: true,
+		MaxMessageSize:    16,
+	}
+	
+	conn, err := wsConnect(context.Background(), "wss"+strings.TrimPrefix(server.URL, "https"), opts)
+	assert.NoError(t, err)
+	defer conn.Close()
+	
+	header := <-headers
+	assert.Equal(t, "runware-test", header.Get("User-Agent"))
+	assert.Contains(t, header.Get("Sec-Websocket-Extensions"), "permessage-deflate")
+	assert.Equal(t, int32(1), proxied.Load())
+	
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("short")))
+	_, msg, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "short", string(msg))
+	
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("a message over the limit")))
+	_, _, err = conn.ReadMessage()
+	assert.ErrorIs(t, err, websocket.ErrReadLimit)
+	
+	// The server certificate isn't trusted without the TLS config
+	_, err = wsConnect(context.Background(), "wss"+strings.TrimPrefix(server.URL, "https"), DialOptions{})
+	assert.Error(t, err)
+}
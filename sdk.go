@@ -3,45 +3,291 @@ package runware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type SDK struct {
+	// Client is replaced when the SDK reconnects, it's read through currentClient
 	Client Runware
 	
-	sessionKey string
+	sessionKey       string
+	clientMu         sync.RWMutex
+	middlewares      []Middleware
+	middlewaresMu    sync.RWMutex
+	timeout          time.Duration
+	abortEvent       string
+	nsfwPolicy       NSFWPolicy
+	languageDetector LanguageDetector
+	uploadCache      UploadCache
+	dispatch         *dispatcher
+	dispatchOnce     sync.Once
+	listenMu         sync.Mutex
+	listening        Runware
+	listenStop       chan struct{}
+	tasksMu          sync.Mutex
+	tasks            map[string]canceller
+	calls            map[canceller]struct{}
+	closed           atomic.Bool
+	events           *lifecycle
+	eventsOnce       sync.Once
+	pingMu           sync.Mutex
+	
+	// Lazy SDKs connect on first use, SDKs which lost their session reconnect on next use
+	cfg       SDKConfig
+	lazy      bool
+	connectMu sync.Mutex
+	clientSet atomic.Bool
+	connected atomic.Bool
+	// Closed when the connection is replaced, stops its monitors
+	connDone chan struct{}
+	
+	// credentialsMu guards the provider, network round trips are made without it
+	credentialsMu sync.Mutex
+	credentials   CredentialProvider
+	// rotations counts the rotated keys, a session resumed meanwhile keeps the rotated one
+	rotations int
+	rotateMu  sync.Mutex
+	// rotatedKey overrides the key of a dynamic provider until it returns another key than rotatedFrom
+	rotatedKey  string
+	rotatedFrom string
 }
 
+// NewSDK connects the SDK, see NewSDKContext
 func NewSDK(cfg SDKConfig) (*SDK, error) {
+	return NewSDKContext(context.Background(), cfg)
+}
+
+// NewSDKContext connects the SDK, ctx bounds the connection. With SDKConfig.LazyConnect the
+// connection is deferred to the first request and NewSDKContext doesn't block
+func NewSDKContext(ctx context.Context, cfg SDKConfig) (*SDK, error) {
+	sdk := &SDK{
+		middlewares:      cfg.Middlewares,
+		timeout:          cfg.RequestTimeout,
+		abortEvent:       cfg.AbortEvent,
+		nsfwPolicy:       cfg.NSFWPolicy,
+		languageDetector: cfg.LanguageDetector,
+		uploadCache:      cfg.UploadCache,
+		events:           newLifecycle(cfg.OnLifecycle),
+		cfg:              cfg,
+		lazy:             cfg.LazyConnect,
+		credentials:      cfg.Credentials,
+	}
+	if sdk.credentials == nil && cfg.APIKey != "" {
+		sdk.credentials = StaticCredentials(cfg.APIKey)
+	}
+	if sdk.languageDetector == nil {
+		sdk.languageDetector = ScriptLanguageDetector{}
+	}
 	
-	client, err := makeClient(cfg)
-	if err != nil {
+	if cfg.LazyConnect {
+		return sdk, nil
+	}
+	
+	if err := sdk.connect(ctx); err != nil {
 		return nil, err
 	}
+	return sdk, nil
+}
+
+// connect creates the client and opens the session
+func (sdk *SDK) connect(ctx context.Context) error {
+	sdk.lifecycle().emit(LifecycleEvent{Type: EventConnecting})
 	
-	sdk := &SDK{
-		Client: client,
+	// The key is resolved on every attempt, the provider can have been rotated meanwhile
+	sdk.credentialsMu.Lock()
+	credentials := sdk.credentials
+	sdk.credentialsMu.Unlock()
+	
+	cfg := sdk.cfg
+	if credentials != nil {
+		apiKey, err := sdk.providerAPIKey(ctx, credentials)
+		if err != nil {
+			return err
+		}
+		cfg.APIKey = apiKey
+	}
+	
+	// The previous connection lost its session, it's replaced
+	_ = sdk.disconnect(sdk.cfg.Client == nil)
+	
+	client, err := makeClient(ctx, cfg, sdk.clientLifecycle)
+	if err != nil {
+		return err
+	}
+	
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = client.APIKey()
 	}
 	
-	res, err := sdk.Connect(context.Background(), NewConnectReq{
-		APIKey: sdk.Client.APIKey(),
+	sdk.clientMu.Lock()
+	sdk.Client = client
+	sdk.clientMu.Unlock()
+	sdk.clientSet.Store(true)
+	
+	res, err := sdk.Connect(ctx, NewConnectReq{
+		APIKey: apiKey,
 	})
+	// Stateless transports have no session, the key is sent with every request
+	if errors.Is(err, ErrNotSupported) {
+		res, err = &NewConnectResp{}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("%w:[%s]", ErrWsDial, err.Error())
+		// The failed client is not listened to anymore
+		sdk.stopListening()
+		sdk.clientSet.Store(false)
+		if sdk.cfg.Client == nil {
+			_ = client.Close()
+		}
+		return fmt.Errorf("%w:[%w]", ErrWsDial, err)
 	}
 	
+	sdk.clientMu.Lock()
 	sdk.sessionKey = res.ConnectionSessionUUID
+	sdk.clientMu.Unlock()
+	sdk.connDone = make(chan struct{})
+	sdk.connected.Store(true)
 	
-	log.Println("Connected", sdk.sessionKey)
+	log.Println("Connected", res.ConnectionSessionUUID)
+	sdk.lifecycle().emit(LifecycleEvent{Type: EventConnected, SessionUUID: res.ConnectionSessionUUID})
 	
 	// Start reconnection monitor
-	go sdk.onReconnected()
+	go sdk.onReconnected(client, sdk.connDone)
 	
-	return sdk, nil
+	if watcher, ok := credentials.(CredentialWatcher); ok {
+		go sdk.watchCredentials(watcher, sdk.connDone)
+	}
+	
+	return nil
 }
 
+// disconnect stops the monitors and the dispatching of the current connection, its client is closed with closeClient
+func (sdk *SDK) disconnect(closeClient bool) error {
+	if sdk.connDone != nil {
+		close(sdk.connDone)
+		sdk.connDone = nil
+	}
+	sdk.connected.Store(false)
+	sdk.stopListening()
+	
+	var err error
+	if client := sdk.currentClient(); closeClient && client != nil && sdk.hasClient() {
+		err = client.Close()
+	}
+	sdk.clientSet.Store(false)
+	return err
+}
+
+// Close cancels the pending calls, stops the background goroutines of the SDK and closes its client.
+// The SDK can't be used afterwards, its requests fail with ErrSDKClosed
+func (sdk *SDK) Close() error {
+	if sdk.closed.Swap(true) {
+		return nil
+	}
+	
+	// The calls are cancelled first, their abort event is sent on the open client
+	sdk.tasksMu.Lock()
+	calls := make([]canceller, 0, len(sdk.calls))
+	for call := range sdk.calls {
+		calls = append(calls, call)
+	}
+	sdk.tasksMu.Unlock()
+	for _, call := range calls {
+		call.Cancel()
+	}
+	
+	sdk.connectMu.Lock()
+	defer sdk.connectMu.Unlock()
+	
+	return sdk.disconnect(true)
+}
+
+// clientLifecycle forwards the client events, the session is lost once the client gave up reconnecting
+func (sdk *SDK) clientLifecycle(ev LifecycleEvent) {
+	if ev.Type == EventDisconnected && errors.Is(ev.Err, ErrWsReconnect) {
+		sdk.connected.Store(false)
+	}
+	sdk.lifecycle().emit(ev)
+}
+
+// ensureConnected connects lazy SDKs on first use and SDKs which lost their session,
+// retrying up to SDKConfig.ConnectAttempts times
+func (sdk *SDK) ensureConnected(ctx context.Context) error {
+	if sdk.closed.Load() {
+		return ErrSDKClosed
+	}
+	// SDKs built around a client without NewSDKContext have nothing to connect
+	if sdk.connected.Load() || (!sdk.lazy && !sdk.clientSet.Load()) {
+		return nil
+	}
+	
+	sdk.connectMu.Lock()
+	defer sdk.connectMu.Unlock()
+	
+	if sdk.closed.Load() {
+		return ErrSDKClosed
+	}
+	if sdk.connected.Load() {
+		return nil
+	}
+	
+	attempts := sdk.cfg.ConnectAttempts
+	if attempts < 1 {
+		attempts = defaultConnectAttempts
+	}
+	
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = sdk.connect(ctx); err == nil {
+			return nil
+		}
+		log.Printf("Connect attempt %d failed: %s\n", attempt, err)
+		
+		// Retrying can't fix the credentials
+		if errors.Is(err, ErrInvalidApiKey) || errors.Is(err, ErrApiKeyRequired) || attempt == attempts {
+			break
+		}
+		
+		select {
+		case <-time.After(time.Duration(attempt) * connectRetryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// currentClient returns the client of the current connection
+func (sdk *SDK) currentClient() Runware {
+	sdk.clientMu.RLock()
+	defer sdk.clientMu.RUnlock()
+	return sdk.Client
+}
+
+// session returns the UUID of the current session
+func (sdk *SDK) session() string {
+	sdk.clientMu.RLock()
+	defer sdk.clientMu.RUnlock()
+	return sdk.sessionKey
+}
+
+// hasClient reports whether the client is created, lazy SDKs create it on first use
+func (sdk *SDK) hasClient() bool {
+	return !sdk.lazy || sdk.clientSet.Load()
+}
+
+// OnError returns the error of an error frame, see parseErrorFrame
 func (sdk *SDK) OnError(msg map[string]interface{}) (error, bool) {
+	return parseErrorFrame(msg)
+}
+
+// parseErrorFrame returns the error of a decoded frame, and whether the frame is an error
+func parseErrorFrame(msg map[string]interface{}) (error, bool) {
 	var (
 		hasError       = false
 		err      error = nil
@@ -60,6 +306,10 @@ func (sdk *SDK) OnError(msg map[string]interface{}) (error, bool) {
 				// Add more
 			default:
 				err = ErrWsUnknownError
+				// Both sentinels match errors about unknown images
+				if parameter, ok := unknownImageParameter(msg); ok {
+					err = fmt.Errorf("%w:%w", ErrWsUnknownError, unknownImageError{parameter: parameter})
+				}
 			}
 		}
 	}
@@ -70,21 +320,56 @@ func (sdk *SDK) OnError(msg map[string]interface{}) (error, bool) {
 	return nil, false
 }
 
-func (sdk *SDK) onReconnected() {
+// onReconnected resumes the session on every reconnection of client, until done is closed
+func (sdk *SDK) onReconnected(client Runware, done chan struct{}) {
+	reconnected := client.Reconnected()
 	for {
 		select {
-		case <-sdk.Client.Reconnected():
-			_, err := sdk.Connect(context.Background(), NewConnectReq{
-				APIKey:                sdk.Client.APIKey(),
-				ConnectionSessionUUID: sdk.sessionKey,
-			})
+		case <-done:
+			return
+		case <-reconnected:
+			err := sdk.resumeSession(context.Background())
 			if err != nil {
 				log.Println("Reconnect failed:", err)
+				// The next request opens a new session
+				sdk.connected.Store(false)
+				sdk.lifecycle().emit(LifecycleEvent{Type: EventDisconnected, Err: err})
+				continue
 			}
+			sdk.lifecycle().emit(LifecycleEvent{Type: EventSessionResumed, SessionUUID: sdk.session()})
 		}
 	}
 }
 
+// resumeSession restores the session after a reconnection, with the current key of the credential provider
+func (sdk *SDK) resumeSession(ctx context.Context) error {
+	sdk.credentialsMu.Lock()
+	rotations := sdk.rotations
+	sdk.credentialsMu.Unlock()
+	
+	apiKey, err := sdk.credentialsAPIKey(ctx)
+	if err != nil {
+		return err
+	}
+	
+	if _, err = sdk.Connect(ctx, NewConnectReq{
+		APIKey:                apiKey,
+		ConnectionSessionUUID: sdk.session(),
+	}); err != nil {
+		return err
+	}
+	
+	sdk.credentialsMu.Lock()
+	defer sdk.credentialsMu.Unlock()
+	
+	// A key rotated during the round trip is newer than apiKey
+	client := sdk.currentClient()
+	if setter, ok := client.(apiKeySetter); ok && rotations == sdk.rotations && apiKey != client.APIKey() {
+		setter.SetAPIKey(apiKey)
+	}
+	return nil
+}
+
 type Request struct {
 	ID            string
 	Event         string
@@ -100,15 +385,57 @@ func (req Request) ToEvent() ([]byte, error) {
 	return json.Marshal(reqM)
 }
 
-func makeClient(cfg SDKConfig) (Runware, error) {
+// requestTimeout returns the time requests wait for their response
+func (sdk *SDK) requestTimeout() time.Duration {
+	if sdk.timeout > 0 {
+		return sdk.timeout
+	}
+	return timeoutSendResponse * time.Second
+}
+
+// send encodes the request and writes it to the client
+func (sdk *SDK) send(req Request) error {
+	bSendReq, err := req.ToEvent()
+	if err != nil {
+		return err
+	}
+	
+	return sdk.currentClient().Send(bSendReq)
+}
+
+func makeClient(ctx context.Context, cfg SDKConfig, onLifecycle func(LifecycleEvent)) (Runware, error) {
 	if cfg.Client != nil {
 		return cfg.Client, nil
 	}
 	
-	client, err := New(RunwareConfig{
-		APIKey:    cfg.APIKey,
-		ConnAddr:  cfg.ConnAddr,
-		KeepAlive: false,
+	if cfg.Transport == TransportHTTP {
+		return NewHTTP(RunwareConfig{
+			APIKey:      cfg.APIKey,
+			ConnAddr:    cfg.ConnAddr,
+			DialOptions: cfg.DialOptions,
+			OnLifecycle: onLifecycle,
+		}, cfg.HTTPClient)
+	}
+	
+	if cfg.PoolSize > 1 {
+		return NewPool(PoolConfig{
+			RunwareConfig: RunwareConfig{
+				APIKey:      cfg.APIKey,
+				ConnAddr:    cfg.ConnAddr,
+				KeepAlive:   false,
+				DialOptions: cfg.DialOptions,
+				OnLifecycle: onLifecycle,
+			},
+			Size: cfg.PoolSize,
+		})
+	}
+	
+	client, err := NewContext(ctx, RunwareConfig{
+		APIKey:      cfg.APIKey,
+		ConnAddr:    cfg.ConnAddr,
+		KeepAlive:   false,
+		DialOptions: cfg.DialOptions,
+		OnLifecycle: onLifecycle,
 	})
 	if err != nil {
 		return nil, err
@@ -1,8 +1,16 @@
 package runware
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 	
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +24,7 @@ type MockRunware struct {
 	SendFunc          func([]byte) error
 	ListenFunc        func() chan []byte
 	ReconnectedFunc   func() chan struct{}
-	ReconnectedCalled bool
+	ReconnectedCalled atomic.Bool
 	Conn              *websocket.Conn
 }
 
@@ -57,7 +65,7 @@ func (m *MockRunware) Listen() chan []byte {
 
 func (m *MockRunware) Reconnected() chan struct{} {
 	if m.ReconnectedFunc != nil {
-		m.ReconnectedCalled = true
+		m.ReconnectedCalled.Store(true)
 		return m.ReconnectedFunc()
 	}
 	return nil
@@ -167,3 +175,209 @@ func (s *SDKTestSuite) TearDownTest() {
 func Test_SDK(t *testing.T) {
 	suite.Run(t, new(SDKTestSuite))
 }
+
+func TestLazyConnect(t *testing.T) {
+	server := newFakeServer().acceptSessions(0)
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{
+		Client:      server.client(),
+		LazyConnect: true,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, receivedFake[NewConnectReq](server, NewConnection))
+	
+	health := sdk.connectionHealth()
+	assert.False(t, health.Connected)
+	assert.Empty(t, health.SessionUUID)
+	
+	_, err = sdk.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, receivedFake[NewConnectReq](server, NewConnection), 1)
+	assert.Equal(t, "session-1", sdk.sessionKey)
+	
+	// The session is kept for the next requests
+	_, err = sdk.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, receivedFake[NewConnectReq](server, NewConnection), 1)
+}
+
+func TestLazyConnectRetries(t *testing.T) {
+	server := newFakeServer().acceptSessions(1)
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{
+		Client:          server.client(),
+		LazyConnect:     true,
+		ConnectAttempts: 2,
+	})
+	assert.NoError(t, err)
+	
+	_, err = sdk.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, receivedFake[NewConnectReq](server, NewConnection), 2)
+	
+	failing := newFakeServer().acceptSessions(math.MaxInt32)
+	sdk, err = NewSDKContext(context.Background(), SDKConfig{
+		Client:          failing.client(),
+		LazyConnect:     true,
+		ConnectAttempts: 3,
+	})
+	assert.NoError(t, err)
+	
+	// The caller context stops the retries
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = sdk.Ping(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, receivedFake[NewConnectReq](failing, NewConnection), 1)
+}
+
+func TestLazyConnectRejectedKey(t *testing.T) {
+	server := newFakeServer()
+	handleFake(server, NewConnection, func(req NewConnectReq) error {
+		server.replyError(19, "Invalid API key", "")
+		return nil
+	})
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{
+		Client:          server.client(),
+		LazyConnect:     true,
+		ConnectAttempts: 3,
+	})
+	assert.NoError(t, err)
+	
+	// Retrying can't fix a rejected key
+	_, err = sdk.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrWsDial)
+	assert.ErrorIs(t, err, ErrInvalidApiKey)
+	assert.Len(t, receivedFake[NewConnectReq](server, NewConnection), 1)
+	
+	sdk, err = NewSDKContext(context.Background(), SDKConfig{
+		Client:      server.client(),
+		Credentials: StaticCredentials(""),
+		LazyConnect: true,
+	})
+	assert.NoError(t, err)
+	_, err = sdk.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrCredentials)
+	assert.ErrorIs(t, err, ErrApiKeyRequired)
+}
+
+func TestNewSDKContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	
+	_, err := NewSDKContext(ctx, SDKConfig{
+		APIKey:   "key",
+		ConnAddr: ConnAddr("ws" + strings.TrimPrefix(newRunwareWsServer(t).URL, "http")),
+	})
+	assert.ErrorIs(t, err, ErrWsDial)
+}
+
+func TestLazyConnectStopsFailedClients(t *testing.T) {
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{
+		Client:          newFakeServer().acceptSessions(math.MaxInt32).client(),
+		LazyConnect:     true,
+		ConnectAttempts: 1,
+	})
+	assert.NoError(t, err)
+	
+	_, err = sdk.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrWsDial)
+	
+	// The failed client is not listened to anymore
+	sdk.listenMu.Lock()
+	assert.Nil(t, sdk.listening)
+	assert.Nil(t, sdk.listenStop)
+	sdk.listenMu.Unlock()
+}
+
+func TestSessionLostReconnects(t *testing.T) {
+	server := newFakeServer().acceptSessions(0)
+	answer := server.handlers[NewConnection]
+	server.handlers[NewConnection] = func(data json.RawMessage) error {
+		// The session can't be resumed after the reconnection
+		if len(receivedFake[NewConnectReq](server, NewConnection)) == 2 {
+			return errors.New("session expired")
+		}
+		return answer(data)
+	}
+	
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{Client: server.client()})
+	assert.NoError(t, err)
+	assert.False(t, sdk.lazy)
+	assert.True(t, sdk.connected.Load())
+	
+	server.reconnected <- struct{}{}
+	assert.Eventually(t, func() bool {
+		return !sdk.connected.Load()
+	}, time.Second, 5*time.Millisecond)
+	
+	// The next request opens a new session
+	_, err = sdk.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, receivedFake[NewConnectReq](server, NewConnection), 3)
+	assert.True(t, sdk.connected.Load())
+}
+
+func TestReconnectDuringHealth(t *testing.T) {
+	server := newFakeServer().acceptSessions(0)
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{Client: server.client()})
+	assert.NoError(t, err)
+	
+	for i := 0; i < 3; i++ {
+		// The session is lost, the health is read while the next request reconnects
+		sdk.connected.Store(false)
+		
+		var wg sync.WaitGroup
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				health, err := sdk.Health(context.Background())
+				assert.NoError(t, err)
+				assert.True(t, health.Healthy)
+			}()
+		}
+		wg.Wait()
+	}
+	assert.Equal(t, "session-1", sdk.session())
+}
+
+func TestSDKClose(t *testing.T) {
+	server := newFakeServer().acceptSessions(0)
+	sdk, err := NewSDKContext(context.Background(), SDKConfig{Client: server.client()})
+	assert.NoError(t, err)
+	
+	// The server never answers the task
+	call, err := Start(context.Background(), sdk, Operation[NewTaskReq, NewTaskResp]{
+		Event:         NewTask,
+		ResponseEvent: NewImage,
+		TaskUUID:      "task-1",
+		Request:       NewTaskReq{TaskUUID: "task-1"},
+	})
+	assert.NoError(t, err)
+	
+	assert.NoError(t, sdk.Close())
+	assert.NoError(t, sdk.Close())
+	
+	// Pending calls are cancelled
+	select {
+	case <-call.Done():
+	case <-time.After(time.Second):
+		t.Fatal("pending call not cancelled")
+	}
+	_, err = call.Wait(context.Background(), time.Second)
+	assert.ErrorIs(t, err, ErrRequestCancelled)
+	assert.True(t, server.closed.Load())
+	
+	// The background goroutines are stopped
+	sdk.listenMu.Lock()
+	assert.Nil(t, sdk.listening)
+	sdk.listenMu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case server.reconnected <- struct{}{}:
+		t.Fatal("reconnections still monitored")
+	case <-time.After(50 * time.Millisecond):
+	}
+	
+	_, err = sdk.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrSDKClosed)
+}
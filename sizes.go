@@ -0,0 +1,151 @@
+package runware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	dimensionStep = 64
+	minDimension  = 128
+	maxDimension  = 2048
+)
+
+// Size dimensions of a size preset
+type Size struct {
+	ID     int
+	Width  int
+	Height int
+}
+
+// AspectRatio width / height ratio of the size
+func (s Size) AspectRatio() float64 {
+	return float64(s.Width) / float64(s.Height)
+}
+
+var sizes = []Size{
+	{ID: SizeSquare512, Width: 512, Height: 512},
+	{ID: SizePortrait2to3, Width: 512, Height: 768},
+	{ID: SizePortrait1to2, Width: 512, Height: 1024},
+	{ID: SizeLandscape2to3, Width: 768, Height: 512},
+	{ID: SizeLandscape2to1, Width: 1024, Height: 512},
+	{ID: SizeLandscape4to3, Width: 683, Height: 512},
+	{ID: SizeLandscape16to9, Width: 910, Height: 512},
+	{ID: SizePortrait9to16, Width: 512, Height: 910},
+	{ID: SizePortrait3to4, Width: 512, Height: 683},
+	{ID: SizeSquare1024SDXL, Width: 1024, Height: 1024},
+	{ID: SizeLandscape16to9SDXL, Width: 1344, Height: 768},
+	{ID: SizePortrait9to16SDXL, Width: 768, Height: 1344},
+	{ID: SizePortrait2to3SDXL, Width: 832, Height: 1216},
+	{ID: SizeLandscape3to2SDXL, Width: 1216, Height: 832},
+}
+
+// SizeByID returns the dimensions of a size preset
+func SizeByID(id int) (Size, bool) {
+	for _, s := range sizes {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Size{}, false
+}
+
+// ParseAspectRatio parses ratios as `16:9` or `1.77`
+func ParseAspectRatio(v string) (float64, error) {
+	var (
+		ratio float64
+		err   error
+	)
+	
+	if w, h, ok := strings.Cut(v, ":"); ok {
+		var width, height float64
+		width, err = strconv.ParseFloat(strings.TrimSpace(w), 64)
+		if err == nil {
+			height, err = strconv.ParseFloat(strings.TrimSpace(h), 64)
+		}
+		if err == nil && height != 0 {
+			ratio = width / height
+		}
+	} else {
+		ratio, err = strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	
+	// ParseFloat accepts `NaN` and `Inf`, they match no size
+	if err != nil || ratio <= 0 || math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return 0, fmt.Errorf("%w:[%s][%s]", ErrFieldIncorrectVal, "aspectRatio", v)
+	}
+	return ratio, nil
+}
+
+// AspectRatioSize returns the size preset of the model closest to the aspect ratio (width / height)
+func AspectRatioSize(model Model, ratio float64) Size {
+	var (
+		nearest  Size
+		bestDiff = math.MaxFloat64
+	)
+	
+	for _, id := range model.SizeIds {
+		s, ok := SizeByID(id)
+		if !ok {
+			continue
+		}
+		
+		// Compare on a log scale so that 2:1 and 1:2 are equally far from 1:1
+		diff := math.Abs(math.Log(s.AspectRatio()) - math.Log(ratio))
+		if diff < bestDiff {
+			nearest, bestDiff = s, diff
+		}
+	}
+	
+	return nearest
+}
+
+// AspectRatioDimensions returns custom width and height matching the aspect ratio (width / height)
+// with the pixel count of the model default size, rounded to multiples of 64
+func AspectRatioDimensions(model Model, ratio float64) (int, int) {
+	pixels := float64(512 * 512)
+	if s, ok := SizeByID(model.Defaults.SizeId); ok {
+		pixels = float64(s.Width * s.Height)
+	}
+	
+	width := roundDimension(math.Sqrt(pixels * ratio))
+	height := roundDimension(math.Sqrt(pixels / ratio))
+	
+	return width, height
+}
+
+func roundDimension(v float64) int {
+	d := int(math.Round(v/dimensionStep)) * dimensionStep
+	return min(max(d, minDimension), maxDimension)
+}
+
+func hasCustomDimensions(req NewTaskReq) bool {
+	return req.Width != 0 || req.Height != 0
+}
+
+func validateDimensions(req NewTaskReq) error {
+	if !hasCustomDimensions(req) {
+		return nil
+	}
+	
+	if req.SizeId != 0 {
+		return fmt.Errorf("%w:[%s]", ErrFieldIncorrectVal, "sizeId and width/height are exclusive")
+	}
+	
+	dimensions := []struct {
+		field string
+		value int
+	}{
+		{"width", req.Width},
+		{"height", req.Height},
+	}
+	for _, d := range dimensions {
+		if d.value < minDimension || d.value > maxDimension || d.value%dimensionStep != 0 {
+			return fmt.Errorf("%w:[%s][%d-%d, multiple of %d]", ErrFieldIncorrectVal, d.field, minDimension, maxDimension, dimensionStep)
+		}
+	}
+	
+	return nil
+}
@@ -0,0 +1,97 @@
+package runware
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAspectRatio(t *testing.T) {
+	ratio, err := ParseAspectRatio("16:9")
+	assert.NoError(t, err)
+	assert.InDelta(t, 16.0/9.0, ratio, 0.0001)
+	
+	ratio, err = ParseAspectRatio("1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, ratio)
+	
+	for _, v := range []string{"16:0", "NaN", "NaN:1", "Inf", "+Inf:9", "1:Inf"} {
+		_, err = ParseAspectRatio(v)
+		assert.ErrorIs(t, err, ErrFieldIncorrectVal, v)
+	}
+}
+
+func TestAspectRatioSize(t *testing.T) {
+	sdxl, _ := ModelByID(ModelSDXL)
+	sd15, _ := ModelByID(ModelDreamshaper)
+	
+	assert.Equal(t, SizeLandscape16to9SDXL, AspectRatioSize(sdxl, 16.0/9.0).ID)
+	assert.Equal(t, SizePortrait2to3SDXL, AspectRatioSize(sdxl, 2.0/3.0).ID)
+	assert.Equal(t, SizeSquare512, AspectRatioSize(sd15, 1).ID)
+	assert.Equal(t, SizeLandscape4to3, AspectRatioSize(sd15, 4.0/3.0).ID)
+}
+
+func TestAspectRatioDimensions(t *testing.T) {
+	sdxl, _ := ModelByID(ModelSDXL)
+	
+	width, height := AspectRatioDimensions(sdxl, 16.0/9.0)
+	assert.Equal(t, 1344, width)
+	assert.Equal(t, 768, height)
+	
+	req := NewTaskReq{ModelId: sdxl.ID.String(), PromptText: "prompt", Width: width, Height: height}
+	assert.NoError(t, validateNewTaskReq(req))
+	assert.NoError(t, validateModelCompatibility(req))
+}
+
+func TestValidateDimensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     NewTaskReq
+		wantErr error
+	}{
+		{
+			name: "Size preset only",
+			req:  NewTaskReq{SizeId: SizeSquare512},
+		},
+		{
+			name: "Valid dimensions",
+			req:  NewTaskReq{Width: 640, Height: 448},
+		},
+		{
+			name:    "Not a multiple of 64",
+			req:     NewTaskReq{Width: 650, Height: 448},
+			wantErr: ErrFieldIncorrectVal,
+		},
+		{
+			name:    "Missing height",
+			req:     NewTaskReq{Width: 640},
+			wantErr: ErrFieldIncorrectVal,
+		},
+		{
+			name:    "Size preset and dimensions",
+			req:     NewTaskReq{SizeId: SizeSquare512, Width: 640, Height: 448},
+			wantErr: ErrFieldIncorrectVal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDimensions(tt.req)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestModelPixelBounds(t *testing.T) {
+	req := mergeNewTaskReqWithDefaults(&NewTaskReq{
+		PromptText: "prompt",
+		ModelId:    ModelDreamshaper.String(),
+		Width:      2048,
+		Height:     2048,
+	})
+	assert.Equal(t, 0, req.SizeId)
+	assert.ErrorIs(t, validateModelCompatibility(*req), ErrModelIncompatible)
+}
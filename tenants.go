@@ -0,0 +1,311 @@
+package runware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultTenantIdleTimeout = 10 * time.Minute
+
+type TenantManagerConfig struct {
+	// SDKConfig shared by the tenants, its APIKey, Credentials, Client and UploadCache are ignored
+	SDKConfig
+	// IdleTimeout closes the connection of tenants without requests, defaults to 10 minutes
+	IdleTimeout time.Duration
+	// UploadCacheTTL gives every tenant its own upload cache when positive, image UUIDs are not shared between accounts
+	UploadCacheTTL time.Duration
+	// NewSDK creates the SDK of a tenant, defaults to NewSDKContext
+	NewSDK func(ctx context.Context, cfg SDKConfig) (*SDK, error)
+}
+
+// TenantManager serves several accounts, it creates an SDK per API key on first use and closes the idle ones
+type TenantManager struct {
+	cfg  TenantManagerConfig
+	done chan struct{}
+	
+	mu      sync.Mutex
+	tenants map[string]*tenant
+	closed  bool
+}
+
+type tenant struct {
+	sdk   *SDK
+	err   error
+	ready chan struct{}
+	
+	// Guarded by TenantManager.mu
+	inFlight int
+	lastUsed time.Time
+}
+
+func NewTenantManager(cfg TenantManagerConfig) *TenantManager {
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultTenantIdleTimeout
+	}
+	if cfg.NewSDK == nil {
+		cfg.NewSDK = NewSDKContext
+	}
+	
+	m := &TenantManager{
+		cfg:     cfg,
+		done:    make(chan struct{}),
+		tenants: make(map[string]*tenant),
+	}
+	go m.evictLoop()
+	
+	return m
+}
+
+// SDK returns the SDK of the tenant, it's created on first use. The SDK can be closed once idle,
+// it must not be kept
+func (m *TenantManager) SDK(ctx context.Context, apiKey string) (*SDK, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	release()
+	
+	return sdk, nil
+}
+
+// acquire returns the SDK of the tenant, it isn't evicted until released
+func (m *TenantManager) acquire(ctx context.Context, apiKey string) (*SDK, func(), error) {
+	if apiKey == "" {
+		return nil, nil, ErrApiKeyRequired
+	}
+	
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, nil, ErrTenantManagerClosed
+	}
+	
+	t, ok := m.tenants[apiKey]
+	if !ok {
+		t = &tenant{ready: make(chan struct{})}
+		m.tenants[apiKey] = t
+		go m.create(apiKey, t)
+	}
+	t.inFlight++
+	m.mu.Unlock()
+	
+	release := func() {
+		m.mu.Lock()
+		t.inFlight--
+		t.lastUsed = time.Now()
+		m.mu.Unlock()
+	}
+	
+	select {
+	case <-t.ready:
+	case <-ctx.Done():
+		release()
+		return nil, nil, ctx.Err()
+	}
+	
+	if t.err != nil {
+		release()
+		return nil, nil, t.err
+	}
+	return t.sdk, release, nil
+}
+
+// create connects the SDK of the tenant, failed tenants are forgotten so the next request retries
+func (m *TenantManager) create(apiKey string, t *tenant) {
+	cfg := m.cfg.SDKConfig
+	cfg.APIKey = apiKey
+	cfg.Credentials = nil
+	cfg.Client = nil
+	cfg.UploadCache = nil
+	if m.cfg.UploadCacheTTL > 0 {
+		cfg.UploadCache = NewMemoryUploadCache(m.cfg.UploadCacheTTL)
+	}
+	
+	// The tenant is shared by the waiting requests, the connection is not bound to the first one
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutSendResponse*time.Second)
+	defer cancel()
+	
+	t.sdk, t.err = m.cfg.NewSDK(ctx, cfg)
+	
+	m.mu.Lock()
+	if t.err != nil && m.tenants[apiKey] == t {
+		delete(m.tenants, apiKey)
+	}
+	closed := m.closed
+	m.mu.Unlock()
+	
+	if t.err == nil && closed {
+		t.err = ErrTenantManagerClosed
+		_ = t.sdk.Close()
+	}
+	close(t.ready)
+}
+
+// Tenants returns the number of tenants with an SDK
+func (m *TenantManager) Tenants() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	return len(m.tenants)
+}
+
+// Evict closes the SDK of the tenant, the next request creates a new one
+func (m *TenantManager) Evict(apiKey string) {
+	m.mu.Lock()
+	t, ok := m.tenants[apiKey]
+	delete(m.tenants, apiKey)
+	m.mu.Unlock()
+	
+	if ok {
+		go m.closeTenant(t)
+	}
+}
+
+// Close closes the SDK of every tenant
+func (m *TenantManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	tenants := m.tenants
+	m.tenants = make(map[string]*tenant)
+	m.mu.Unlock()
+	
+	close(m.done)
+	for _, t := range tenants {
+		m.closeTenant(t)
+	}
+	return nil
+}
+
+func (m *TenantManager) closeTenant(t *tenant) {
+	<-t.ready
+	if t.err == nil {
+		_ = t.sdk.Close()
+	}
+}
+
+// evictLoop closes the tenants idle for longer than IdleTimeout
+func (m *TenantManager) evictLoop() {
+	ticker := time.NewTicker(m.cfg.IdleTimeout / 2)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
+		
+		m.evictIdle()
+	}
+}
+
+func (m *TenantManager) evictIdle() {
+	var idle []*tenant
+	
+	m.mu.Lock()
+	for apiKey, t := range m.tenants {
+		if t.inFlight == 0 && !t.lastUsed.IsZero() && time.Since(t.lastUsed) > m.cfg.IdleTimeout {
+			idle = append(idle, t)
+			delete(m.tenants, apiKey)
+		}
+	}
+	m.mu.Unlock()
+	
+	for _, t := range idle {
+		m.closeTenant(t)
+	}
+}
+
+// NewImage generates images with the SDK of the tenant. A timed out task stays in flight, the tenant
+// isn't evicted until its Pending images are collected or cancelled
+func (m *TenantManager) NewImage(ctx context.Context, apiKey string, req NewTaskReq) (*NewTaskResp, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	
+	resp, err := sdk.NewImage(ctx, req)
+	if resp != nil && resp.Pending != nil {
+		go func() {
+			<-resp.Pending.Done()
+			release()
+		}()
+		return resp, err
+	}
+	
+	release()
+	return resp, err
+}
+
+func (m *TenantManager) ImageUpload(ctx context.Context, apiKey string, req NewImageUploadReq) (*NewImageUploadResp, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	return sdk.ImageUpload(ctx, req)
+}
+
+func (m *TenantManager) ImageUpscale(ctx context.Context, apiKey string, req NewUpscaleGanReq) (*NewUpscaleGanResp, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	return sdk.ImageUpscale(ctx, req)
+}
+
+func (m *TenantManager) ImageToText(ctx context.Context, apiKey string, req NewReverseImageClipReq) (*NewReverseImageClipResp, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	return sdk.ImageToText(ctx, req)
+}
+
+func (m *TenantManager) PromptEnhancer(ctx context.Context, apiKey string, req NewPromptEnhanceReq) (*NewPromptEnhanceRes, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	return sdk.PromptEnhancer(ctx, req)
+}
+
+func (m *TenantManager) NewControlNets(ctx context.Context, apiKey string, req NewControlNetsReq) (*NewControlNetsResp, error) {
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	return sdk.NewControlNets(ctx, req)
+}
+
+func (m *TenantManager) Cancel(ctx context.Context, apiKey string, taskUUID string) error {
+	// Without SDK the tenant has no task to cancel
+	m.mu.Lock()
+	_, ok := m.tenants[apiKey]
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	
+	sdk, release, err := m.acquire(ctx, apiKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+	
+	return sdk.Cancel(ctx, taskUUID)
+}
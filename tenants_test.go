@@ -0,0 +1,143 @@
+package runware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantManager(t *testing.T) {
+	var created int32
+	m := NewTenantManager(TenantManagerConfig{
+		IdleTimeout: time.Hour,
+		NewSDK: func(ctx context.Context, cfg SDKConfig) (*SDK, error) {
+			atomic.AddInt32(&created, 1)
+			cfg.Client = newFakeServer().acceptSessions(0).client()
+			return NewSDKContext(ctx, cfg)
+		},
+	})
+	defer m.Close()
+	ctx := context.Background()
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			
+			sdk, err := m.SDK(ctx, "key-a")
+			assert.NoError(t, err)
+			_, err = sdk.Ping(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&created))
+	
+	a, err := m.SDK(ctx, "key-a")
+	assert.NoError(t, err)
+	b, err := m.SDK(ctx, "key-b")
+	assert.NoError(t, err)
+	assert.NotSame(t, a, b)
+	assert.Equal(t, "key-b", b.Client.APIKey())
+	assert.Equal(t, 2, m.Tenants())
+	
+	_, err = m.SDK(ctx, "")
+	assert.ErrorIs(t, err, ErrApiKeyRequired)
+	
+	// Unknown tenants have nothing to cancel
+	assert.NoError(t, m.Cancel(ctx, "key-c", "task"))
+	assert.Equal(t, 2, m.Tenants())
+}
+
+func TestTenantManagerFailure(t *testing.T) {
+	var created int32
+	m := NewTenantManager(TenantManagerConfig{
+		IdleTimeout: time.Hour,
+		NewSDK: func(ctx context.Context, cfg SDKConfig) (*SDK, error) {
+			if atomic.AddInt32(&created, 1) == 1 {
+				return nil, errors.New("connection refused")
+			}
+			cfg.Client = newFakeServer().acceptSessions(0).client()
+			return NewSDKContext(ctx, cfg)
+		},
+	})
+	defer m.Close()
+	
+	_, err := m.SDK(context.Background(), "key")
+	assert.Error(t, err)
+	assert.Equal(t, 0, m.Tenants())
+	
+	// Failed tenants are retried
+	_, err = m.SDK(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&created))
+}
+
+func TestTenantManagerEviction(t *testing.T) {
+	var servers sync.Map
+	m := NewTenantManager(TenantManagerConfig{
+		IdleTimeout: 20 * time.Millisecond,
+		NewSDK: func(ctx context.Context, cfg SDKConfig) (*SDK, error) {
+			server := newFakeServer().acceptSessions(0)
+			servers.Store(cfg.APIKey, server)
+			cfg.Client = server.client()
+			return NewSDKContext(ctx, cfg)
+		},
+	})
+	defer m.Close()
+	
+	_, err := m.SDK(context.Background(), "key")
+	assert.NoError(t, err)
+	
+	assert.Eventually(t, func() bool {
+		return m.Tenants() == 0
+	}, time.Second, 10*time.Millisecond)
+	server, _ := servers.Load("key")
+	assert.Eventually(t, server.(*fakeServer).closed.Load, time.Second, 10*time.Millisecond)
+	
+	_, err = m.SDK(context.Background(), "other-key")
+	assert.NoError(t, err)
+	m.Evict("other-key")
+	assert.Equal(t, 0, m.Tenants())
+	
+	assert.NoError(t, m.Close())
+	_, err = m.SDK(context.Background(), "key")
+	assert.ErrorIs(t, err, ErrTenantManagerClosed)
+}
+
+func TestTenantManagerPendingImages(t *testing.T) {
+	var servers sync.Map
+	m := NewTenantManager(TenantManagerConfig{
+		IdleTimeout: 20 * time.Millisecond,
+		NewSDK: func(ctx context.Context, cfg SDKConfig) (*SDK, error) {
+			server := newFakeServer().acceptSessions(0)
+			servers.Store(cfg.APIKey, server)
+			cfg.Client = server.client()
+			return NewSDKContext(ctx, cfg)
+		},
+	})
+	defer m.Close()
+	m.cfg.RequestTimeout = 20 * time.Millisecond
+	
+	// The server never answers the task
+	resp, err := m.NewImage(context.Background(), "key", NewTaskReq{PromptText: "A cat", NumberResults: 1})
+	assert.ErrorIs(t, err, ErrRequestTimeout)
+	assert.NotNil(t, resp.Pending)
+	
+	// The tenant isn't evicted while its images are pending
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, m.Tenants())
+	
+	resp.Pending.Cancel()
+	assert.Eventually(t, func() bool {
+		return m.Tenants() == 0
+	}, time.Second, 10*time.Millisecond)
+	server, _ := servers.Load("key")
+	assert.Eventually(t, server.(*fakeServer).closed.Load, time.Second, 10*time.Millisecond)
+}
@@ -17,13 +17,13 @@ const (
 
 // Available models
 const (
-	ModelSDXL               = 4
-	ModelRevAnimated        = 13
-	ModelAbsolutereality    = 18
-	ModelCyberrealistic     = 19
-	ModelDreamshaper        = 20
-	ModelGhostmixBakedvae   = 22
-	ModelSamaritan3DCartoon = 25
+	ModelSDXL               ModelID = 4
+	ModelRevAnimated        ModelID = 13
+	ModelAbsolutereality    ModelID = 18
+	ModelCyberrealistic     ModelID = 19
+	ModelDreamshaper        ModelID = 20
+	ModelGhostmixBakedvae   ModelID = 22
+	ModelSamaritan3DCartoon ModelID = 25
 )
 
 // Available processors
@@ -60,6 +60,54 @@ const (
 	SizeLandscape3to2SDXL  = 21
 )
 
+// Available schedulers
+const (
+	SchedulerDDIM                Scheduler = "DDIMScheduler"
+	SchedulerDDPM                Scheduler = "DDPMScheduler"
+	SchedulerDEISMultistep       Scheduler = "DEISMultistepScheduler"
+	SchedulerDPMSolverMultistep  Scheduler = "DPMSolverMultistepScheduler"
+	SchedulerDPMSolverSinglestep Scheduler = "DPMSolverSinglestepScheduler"
+	SchedulerEulerAncestral      Scheduler = "EulerAncestralDiscreteScheduler"
+	SchedulerEuler               Scheduler = "EulerDiscreteScheduler"
+	SchedulerHeun                Scheduler = "HeunDiscreteScheduler"
+	SchedulerKDPM2Ancestral      Scheduler = "KDPM2AncestralDiscreteScheduler"
+	SchedulerKDPM2               Scheduler = "KDPM2DiscreteScheduler"
+	SchedulerLMS                 Scheduler = "LMSDiscreteScheduler"
+	SchedulerPNDM                Scheduler = "PNDMScheduler"
+	SchedulerUniPCMultistep      Scheduler = "UniPCMultistepScheduler"
+)
+
+// Available output formats
+const (
+	OutputFormatJPG  OutputFormat = "JPG"
+	OutputFormatPNG  OutputFormat = "PNG"
+	OutputFormatWEBP OutputFormat = "WEBP"
+)
+
+type Scheduler string
+
+func (s Scheduler) Valid() bool {
+	switch s {
+	case SchedulerDDIM, SchedulerDDPM, SchedulerDEISMultistep, SchedulerDPMSolverMultistep,
+		SchedulerDPMSolverSinglestep, SchedulerEulerAncestral, SchedulerEuler, SchedulerHeun,
+		SchedulerKDPM2Ancestral, SchedulerKDPM2, SchedulerLMS, SchedulerPNDM, SchedulerUniPCMultistep:
+		return true
+	default:
+		return false
+	}
+}
+
+type OutputFormat string
+
+func (f OutputFormat) Valid() bool {
+	switch f {
+	case OutputFormatJPG, OutputFormatPNG, OutputFormatWEBP:
+		return true
+	default:
+		return false
+	}
+}
+
 type ControlNet struct {
 	Preprocessor   string  `json:"preprocessor"`
 	Weight         float64 `json:"weight"`
@@ -75,6 +123,7 @@ type Image struct {
 	BNSFWContent bool   `json:"bNSFWContent"`
 	ImageAltText string `json:"imageAltText"`
 	TaskUUID     string `json:"taskUUID"`
+	Seed         int64  `json:"seed,omitempty"`
 }
 
 type Text struct {
@@ -88,26 +137,39 @@ type Lora struct {
 }
 
 type PreProcessControlNet struct {
-	TaskUUID           string `json:"taskUUID"`
-	PreProcessorType   string `json:"preProcessorType"`
-	GuideImageUUID     string `json:"guideImageUUID"`
-	TaskType           int    `json:"taskType"`
-	Width              int    `json:"width"`
-	Height             int    `json:"height"`
-	LowThresholdCanny  int    `json:"lowThresholdCanny"`
-	HighThresholdCanny int    `json:"highThresholdCanny"`
+	TaskUUID           string     `json:"taskUUID"`
+	PreProcessorType   string     `json:"preProcessorType"`
+	GuideImageUUID     string     `json:"guideImageUUID"`
+	TaskType           int        `json:"taskType"`
+	Width              int        `json:"width"`
+	Height             int        `json:"height"`
+	LowThresholdCanny  int        `json:"lowThresholdCanny"`
+	HighThresholdCanny int        `json:"highThresholdCanny"`
+	NSFWPolicy         NSFWPolicy `json:"-"`
 }
 
 type Task struct {
 	TaskUUID           string       `json:"taskUUID"`
 	ImageInitiatorUUID string       `json:"imageInitiatorUUID,omitempty"`
 	PromptText         string       `json:"promptText"`
+	NegativePrompt     string       `json:"negativePrompt,omitempty"`
 	NumberResults      int          `json:"numberResults"`
 	ModelId            string       `json:"modelId"`
-	SizeId             int          `json:"sizeId"`
+	SizeId             int          `json:"sizeId,omitempty"`
+	Width              int          `json:"width,omitempty"`
+	Height             int          `json:"height,omitempty"`
 	TaskType           int          `json:"taskType"`
-	PromptLanguageId   *string      `json:"promptLanguageId"`
+	PromptLanguageId   *Language    `json:"promptLanguageId"`
 	Offset             int          `json:"offset"`
 	Lora               []Lora       `json:"lora"`
 	ControlNet         []ControlNet `json:"controlNet"`
+	Seed               int64        `json:"seed,omitempty"`
+	Steps              int          `json:"steps,omitempty"`
+	CFGScale           float64      `json:"CFGScale,omitempty"`
+	Scheduler          Scheduler    `json:"scheduler,omitempty"`
+	ClipSkip           int          `json:"clipSkip,omitempty"`
+	Strength           float64      `json:"strength,omitempty"`
+	OutputFormat       OutputFormat `json:"outputFormat,omitempty"`
+	OutputQuality      int          `json:"outputQuality,omitempty"`
+	NSFWPolicy         NSFWPolicy   `json:"-"`
 }
@@ -0,0 +1,171 @@
+package runware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedUpload image previously uploaded to the server
+type CachedUpload struct {
+	ImageUUID string `json:"imageUUID"`
+	ImageSrc  string `json:"imageSrc"`
+}
+
+// UploadCache stores the uploaded images by content hash, so identical images are uploaded once
+type UploadCache interface {
+	Get(ctx context.Context, hash string) (CachedUpload, bool, error)
+	Set(ctx context.Context, hash string, upload CachedUpload) error
+	// Invalidate removes the entries of an image UUID unknown to the server
+	Invalidate(ctx context.Context, imageUUID string) error
+}
+
+type memoryUploadEntry struct {
+	upload    CachedUpload
+	expiresAt time.Time
+}
+
+// MemoryUploadCache in-memory UploadCache, entries expire after their TTL and are removed
+// by the next Set
+type MemoryUploadCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]memoryUploadEntry
+}
+
+// NewMemoryUploadCache returns an in-memory cache, a zero TTL keeps the entries forever
+func NewMemoryUploadCache(ttl time.Duration) *MemoryUploadCache {
+	return &MemoryUploadCache{
+		ttl:     ttl,
+		entries: make(map[string]memoryUploadEntry),
+	}
+}
+
+func (c *MemoryUploadCache) Get(_ context.Context, hash string) (CachedUpload, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	entry, ok := c.entries[hash]
+	if !ok {
+		return CachedUpload{}, false, nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(c.entries, hash)
+		return CachedUpload{}, false, nil
+	}
+	return entry.upload, true, nil
+}
+
+func (c *MemoryUploadCache) Set(_ context.Context, hash string, upload CachedUpload) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	now := time.Now()
+	
+	// Entries which are never read again are removed once expired
+	for h, entry := range c.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(c.entries, h)
+		}
+	}
+	
+	entry := memoryUploadEntry{upload: upload}
+	if c.ttl > 0 {
+		entry.expiresAt = now.Add(c.ttl)
+	}
+	c.entries[hash] = entry
+	return nil
+}
+
+func (c *MemoryUploadCache) Invalidate(_ context.Context, imageUUID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	for hash, entry := range c.entries {
+		if entry.upload.ImageUUID == imageUUID {
+			delete(c.entries, hash)
+		}
+	}
+	return nil
+}
+
+// imageContentHash hashes the decoded image so data URIs and plain base64 of an image match
+func imageContentHash(imageBase64 string) string {
+	if _, data, ok := strings.Cut(imageBase64, ","); ok && strings.HasPrefix(imageBase64, "data:") {
+		imageBase64 = data
+	}
+	
+	content, err := base64.StdEncoding.DecodeString(imageBase64)
+	if err != nil {
+		content = []byte(imageBase64)
+	}
+	
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// imageUUIDParameters request fields referring to uploaded images, the REST names map to the
+// websocket fields. REST tasks sending an `inputImage` have a single image, it maps to any field
+var imageUUIDParameters = map[string]string{
+	"imageUUID":          "imageUUID",
+	"imageInitiatorUUID": "imageInitiatorUUID",
+	"imageMaskUUID":      "imageMaskUUID",
+	"guideImageUUID":     "guideImageUUID",
+	"seedImage":          "imageInitiatorUUID",
+	"inputImage":         "",
+}
+
+// unknownImageError an image UUID unknown to the server, parameter is the rejected request field
+type unknownImageError struct {
+	parameter string
+}
+
+func (e unknownImageError) Error() string {
+	return fmt.Sprintf("%s:[%s]", ErrUnknownImageUUID.Error(), e.parameter)
+}
+
+func (e unknownImageError) Is(target error) bool {
+	return target == ErrUnknownImageUUID
+}
+
+// unknownImageParameter returns the image field rejected by a server error frame, the frame
+// names it in `parameter`
+func unknownImageParameter(msg map[string]interface{}) (string, bool) {
+	parameter, ok := msg["parameter"].(string)
+	if !ok {
+		return "", false
+	}
+	_, ok = imageUUIDParameters[parameter]
+	return parameter, ok
+}
+
+// invalidateUploads drops the cached upload of the image UUID the server didn't know, imageUUIDs
+// are the images of the request by field
+func (sdk *SDK) invalidateUploads(ctx context.Context, err error, imageUUIDs map[string][]string) {
+	var unknown unknownImageError
+	if sdk.uploadCache == nil || !errors.As(err, &unknown) {
+		return
+	}
+	
+	field := imageUUIDParameters[unknown.parameter]
+	for name, uuids := range imageUUIDs {
+		if field != "" && name != field {
+			continue
+		}
+		for _, imageUUID := range uuids {
+			if imageUUID == "" {
+				continue
+			}
+			if cacheErr := sdk.uploadCache.Invalidate(ctx, imageUUID); cacheErr != nil {
+				log.Println("Upload cache invalidation failed", imageUUID, cacheErr)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package runware
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUploadCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryUploadCache(50 * time.Millisecond)
+	
+	assert.NoError(t, cache.Set(ctx, "hash-1", CachedUpload{ImageUUID: "img-1"}))
+	assert.NoError(t, cache.Set(ctx, "hash-2", CachedUpload{ImageUUID: "img-2"}))
+	
+	upload, ok, err := cache.Get(ctx, "hash-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "img-1", upload.ImageUUID)
+	
+	assert.NoError(t, cache.Invalidate(ctx, "img-1"))
+	_, ok, _ = cache.Get(ctx, "hash-1")
+	assert.False(t, ok)
+	
+	time.Sleep(60 * time.Millisecond)
+	_, ok, _ = cache.Get(ctx, "hash-2")
+	assert.False(t, ok)
+	
+	// Expired entries which are never read again are removed by the next Set
+	assert.NoError(t, cache.Set(ctx, "hash-3", CachedUpload{ImageUUID: "img-3"}))
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, cache.Set(ctx, "hash-4", CachedUpload{ImageUUID: "img-4"}))
+	assert.Len(t, cache.entries, 1)
+}
+
+func TestInvalidateUploadsOfParameter(t *testing.T) {
+	ctx := context.Background()
+	sdk := &SDK{uploadCache: NewMemoryUploadCache(0)}
+	for _, imageUUID := range []string{"img-init", "img-guide"} {
+		assert.NoError(t, sdk.uploadCache.Set(ctx, "hash-"+imageUUID, CachedUpload{ImageUUID: imageUUID}))
+	}
+	req := NewTaskReq{ImageInitiatorUUID: "img-init", ControlNet: []ControlNet{{GuideImageUUID: "img-guide"}}}
+	
+	// Only the image named by the error is uploaded again
+	err, _ := parseErrorFrame(map[string]interface{}{"error": true, "errorId": float64(500), "errorMessage": "Invalid guideImageUUID", "parameter": "guideImageUUID"})
+	sdk.invalidateUploads(ctx, err, req.imageUUIDs())
+	
+	_, ok, _ := sdk.uploadCache.Get(ctx, "hash-img-init")
+	assert.True(t, ok)
+	_, ok, _ = sdk.uploadCache.Get(ctx, "hash-img-guide")
+	assert.False(t, ok)
+	
+	// REST errors name the initiator image `seedImage`
+	err, _ = parseErrorFrame(map[string]interface{}{"error": true, "errorId": float64(500), "errorMessage": "Invalid seedImage", "parameter": "seedImage"})
+	sdk.invalidateUploads(ctx, err, req.imageUUIDs())
+	
+	_, ok, _ = sdk.uploadCache.Get(ctx, "hash-img-init")
+	assert.False(t, ok)
+}
+
+func TestImageContentHash(t *testing.T) {
+	plain := base64.StdEncoding.EncodeToString(testPNG)
+	assert.Equal(t, imageContentHash(plain), imageContentHash("data:image/png;base64,"+plain))
+	assert.NotEqual(t, imageContentHash(plain), imageContentHash(base64.StdEncoding.EncodeToString([]byte("other"))))
+}
+
+func TestImageUploadCache(t *testing.T) {
+	var (
+		incoming = make(chan []byte, 10)
+		uploads  = 0
+	)
+	
+	sdk := &SDK{
+		Client: &MockRunware{
+			SendFunc: func(b []byte) error {
+				var msg map[string]map[string]string
+				if err := json.Unmarshal(b, &msg); err != nil {
+					return err
+				}
+				
+				if req, ok := msg[NewImageUpload]; ok {
+					uploads++
+					incoming <- []byte(fmt.Sprintf(`{"newUploadedImageUUID":{"newImageUUID":"img-%d","taskUUID":"%s"}}`, uploads, req["taskUUID"]))
+					return nil
+				}
+				
+				incoming <- []byte(fmt.Sprintf(`{"error":true,"errorId":500,"errorMessage":"imageUUID not found","parameter":"imageUUID","taskUUID":"%s"}`, msg[NewReverseImageClip]["taskUUID"]))
+				return nil
+			},
+			ListenFunc: func() chan []byte {
+				return incoming
+			},
+		},
+		uploadCache: NewMemoryUploadCache(0),
+	}
+	
+	ctx := context.Background()
+	imageBase64 := base64.StdEncoding.EncodeToString(testPNG)
+	
+	first, err := sdk.ImageUpload(ctx, NewImageUploadReq{ImageBase64: imageBase64})
+	assert.NoError(t, err)
+	assert.False(t, first.Cached)
+	
+	second, err := sdk.ImageUpload(ctx, NewImageUploadReq{ImageBase64: "data:image/png;base64," + imageBase64})
+	assert.NoError(t, err)
+	assert.True(t, second.Cached)
+	assert.Equal(t, first.NewImageUUID, second.NewImageUUID)
+	assert.Equal(t, 1, uploads)
+	
+	// The server doesn't know the image anymore, it's uploaded again
+	_, err = sdk.ImageToText(ctx, NewReverseImageClipReq{ImageUUID: first.NewImageUUID})
+	assert.ErrorIs(t, err, ErrUnknownImageUUID)
+	
+	third, err := sdk.ImageUpload(ctx, NewImageUploadReq{ImageBase64: imageBase64})
+	assert.NoError(t, err)
+	assert.False(t, third.Cached)
+	assert.Equal(t, "img-2", third.NewImageUUID)
+	assert.Equal(t, 2, uploads)
+}
+
+func TestUnknownImageError(t *testing.T) {
+	sdk := &SDK{}
+	
+	err, ok := sdk.OnError(map[string]interface{}{"error": true, "errorId": float64(500), "errorMessage": "Invalid imageUUID", "parameter": "imageUUID"})
+	assert.True(t, ok)
+	assert.ErrorIs(t, err, ErrUnknownImageUUID)
+	assert.ErrorIs(t, err, ErrWsUnknownError)
+	
+	// Errors about other UUIDs keep the generic sentinel only
+	err, ok = sdk.OnError(map[string]interface{}{"error": true, "errorId": float64(500), "errorMessage": "Invalid taskUUID", "parameter": "taskUUID"})
+	assert.True(t, ok)
+	assert.ErrorIs(t, err, ErrWsUnknownError)
+	assert.NotErrorIs(t, err, ErrUnknownImageUUID)
+	
+	err, _ = sdk.OnError(map[string]interface{}{"error": true, "errorId": float64(500), "errorMessage": "taskUUID not found"})
+	assert.NotErrorIs(t, err, ErrUnknownImageUUID)
+}